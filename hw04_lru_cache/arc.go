@@ -0,0 +1,152 @@
+package hw04lrucache
+
+import "sync"
+
+// ARC-кэш (Adaptive Replacement Cache): элементы хранятся в очередях t1 (одно обращение)
+// и t2 (несколько обращений), ключи вытесненных из них элементов - в очередях b1 и b2.
+// Попадания в b1 и b2 смещают целевой размер t1, подстраивая кэш под характер нагрузки.
+type arcCache[K comparable, V any] struct {
+	capacity int
+	mtx      sync.Mutex
+	p        int // целевой размер t1
+	t1       *keyedQueue[K, V]
+	t2       *keyedQueue[K, V]
+	b1       *keyedQueue[K, struct{}]
+	b2       *keyedQueue[K, struct{}]
+}
+
+// Создать новый ARC-кэш.
+func NewARCCache(capacity int) Cache {
+	return NewTypedARCCache[Key, interface{}](capacity)
+}
+
+// Создать новый ARC-кэш с ключами типа K и значениями типа V.
+func NewTypedARCCache[K comparable, V any](capacity int) TypedCache[K, V] {
+	c := &arcCache[K, V]{
+		capacity: capacity,
+	}
+	c.init()
+
+	return c
+}
+
+func (c *arcCache[K, V]) init() {
+	c.p = 0
+	c.t1 = newKeyedQueue[K, V](c.capacity)
+	c.t2 = newKeyedQueue[K, V](c.capacity)
+	c.b1 = newKeyedQueue[K, struct{}](c.capacity)
+	c.b2 = newKeyedQueue[K, struct{}](c.capacity)
+}
+
+// Добавить значение в кэш.
+func (c *arcCache[K, V]) Set(key K, value V) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if itm, ok := c.t1.get(key); ok { // повторное обращение
+		c.t1.remove(itm)
+		c.t2.pushFront(key, value)
+		return true
+	}
+
+	if itm, ok := c.t2.get(key); ok {
+		itm.Value.Value = value
+		c.t2.list.MoveToFront(itm)
+		return true
+	}
+
+	if itm, ok := c.b1.get(key); ok { // t1 оказался мал, увеличиваем его
+		delta := 1
+		if c.b2.Len() > c.b1.Len() {
+			delta = c.b2.Len() / c.b1.Len()
+		}
+		c.p = min(c.p+delta, c.capacity)
+
+		c.replace(false)
+		c.b1.remove(itm)
+		c.t2.pushFront(key, value)
+		return false
+	}
+
+	if itm, ok := c.b2.get(key); ok { // t2 оказался мал, уменьшаем t1
+		delta := 1
+		if c.b1.Len() > c.b2.Len() {
+			delta = c.b1.Len() / c.b2.Len()
+		}
+		c.p = max(c.p-delta, 0)
+
+		c.replace(true)
+		c.b2.remove(itm)
+		c.t2.pushFront(key, value)
+		return false
+	}
+
+	c.replace(false)
+	if c.b1.Len() > c.capacity-c.p {
+		c.b1.removeOldest()
+	}
+	if c.b2.Len() > c.p {
+		c.b2.removeOldest()
+	}
+	c.t1.pushFront(key, value)
+
+	return false
+}
+
+// Получить значение из кэша.
+func (c *arcCache[K, V]) Get(key K) (V, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if itm, ok := c.t1.get(key); ok { // повторное обращение
+		c.t1.remove(itm)
+		c.t2.pushFront(key, itm.Value.Value)
+		return itm.Value.Value, true
+	}
+
+	if itm, ok := c.t2.get(key); ok {
+		c.t2.list.MoveToFront(itm)
+		return itm.Value.Value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Очистить кэш.
+func (c *arcCache[K, V]) Clear() {
+	c.mtx.Lock()
+	c.init()
+	c.mtx.Unlock()
+}
+
+// освободить место для нового элемента, если кэш заполнен.
+// inB2 - новый элемент найден в очереди b2.
+func (c *arcCache[K, V]) replace(inB2 bool) {
+	if c.capacity <= 0 || c.t1.Len()+c.t2.Len() < c.capacity {
+		return
+	}
+
+	t1Len := c.t1.Len()
+	if t1Len > 0 && (t1Len > c.p || (t1Len == c.p && inB2)) {
+		excess := c.t1.removeOldest()
+		c.addGhost(c.b1, excess.Key)
+		return
+	}
+
+	excess := c.t2.removeOldest()
+	if excess == nil { // t2 пуст
+		excess = c.t1.removeOldest()
+		c.addGhost(c.b1, excess.Key)
+		return
+	}
+	c.addGhost(c.b2, excess.Key)
+}
+
+// запомнить ключ вытесненного элемента, размер очереди ограничен емкостью кэша.
+func (c *arcCache[K, V]) addGhost(q *keyedQueue[K, struct{}], key K) {
+	if q.Len() >= c.capacity {
+		q.removeOldest()
+	}
+	q.pushFront(key, struct{}{})
+}
@@ -1,84 +1,254 @@
 package hw04lrucache
 
-import "sync"
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
 
 type Key string
 
-type Cache interface {
-	Set(key Key, value interface{}) bool // Добавить значение в кэш.
-	Get(key Key) (interface{}, bool)     // Получить значение из кэша.
-	Clear()                              // Очистить кэш.
+// Cache - кэш со строковыми ключами и значениями произвольного типа.
+type Cache = TypedCache[Key, interface{}]
+
+// TypedCache - кэш с ключами типа K и значениями типа V.
+type TypedCache[K comparable, V any] interface {
+	Set(key K, value V) bool // Добавить значение в кэш.
+	Get(key K) (V, bool)     // Получить значение из кэша.
+	Clear()                  // Очистить кэш.
+}
+
+// LRUCache - LRU-кэш с расширенными возможностями.
+type LRUCache[K comparable, V any] interface {
+	TypedCache[K, V]
+	SetWithTTL(key K, value V, ttl time.Duration) bool    // Добавить значение в кэш с ограниченным временем жизни.
+	SetWithCost(key K, value V, cost int64) (bool, error) // Добавить значение в кэш с заданной стоимостью.
+	Remove(key K) bool                                    // Удалить значение из кэша.
+	DeleteExpired()                                       // Удалить из кэша все устаревшие элементы.
+	OnEvict(fn func(key K, value V, reason EvictReason))  // Уведомлять об удалении элементов из кэша.
+	Stats() Stats                                         // Получить статистику использования кэша.
+	ResetStats()                                          // Сбросить статистику использования кэша.
+	Snapshot(w io.Writer) error                           // Сохранить содержимое кэша.
+	Restore(r io.Reader) error                            // Загрузить сохраненное содержимое в кэш.
+	Close()                                               // Остановить фоновую очистку кэша.
 }
 
-type cacheItem struct {
-	Value interface{}
-	Key   Key
+type cacheItem[K comparable, V any] struct {
+	Value     V
+	Key       K
+	expiresAt time.Time // момент устаревания элемента, нулевое значение - бессрочно
+	cost      int64     // стоимость элемента
 }
 
-type lruCache struct {
+type lruCache[K comparable, V any] struct {
 	capacity int
 	mtx      sync.Mutex
-	queue    List
-	items    map[Key]*ListItem
+	queue    TypedList[*cacheItem[K, V]]
+	items    map[K]*TypedListItem[*cacheItem[K, V]]
+
+	budget int64    // максимальная суммарная стоимость элементов, 0 - без ограничения
+	cost   int64    // текущая суммарная стоимость элементов
+	sizer  Sizer[V] // функция расчета стоимости значения
+
+	onEvict EvictFunc[K, V]  // функция уведомления об удалении элемента
+	evicted []eviction[K, V] // удаленные элементы, ожидающие уведомления
+
+	stats stats // статистика использования
+
+	codec Codec // формат снимка кэша
+
+	clock     Clock
+	stop      chan struct{} // сигнал остановки фоновой очистки
+	stopped   chan struct{} // фоновая очистка завершена
+	closeOnce sync.Once
 }
 
 // Создать новый LRU-кэш.
 func NewCache(capacity int) Cache {
-	return &lruCache{
+	return NewTypedCache[Key, interface{}](capacity)
+}
+
+// Создать новый LRU-кэш с ключами типа K и значениями типа V.
+func NewTypedCache[K comparable, V any](capacity int) TypedCache[K, V] {
+	return newLRUCache[K, V](capacity)
+}
+
+// Создать новый LRU-кэш с расширенными возможностями.
+func NewLRUCache[K comparable, V any](capacity int, opts ...Option) LRUCache[K, V] {
+	return newLRUCache[K, V](capacity, opts...)
+}
+
+func newLRUCache[K comparable, V any](capacity int, opts ...Option) *lruCache[K, V] {
+	cfg := newConfig(opts)
+
+	c := &lruCache[K, V]{
 		capacity: capacity,
 		mtx:      sync.Mutex{},
-		queue:    NewList(),
-		items:    make(map[Key]*ListItem, capacity),
+		queue:    NewTypedList[*cacheItem[K, V]](),
+		items:    make(map[K]*TypedListItem[*cacheItem[K, V]], capacity),
+		budget:   cfg.budget,
+		codec:    cfg.codec,
+		clock:    cfg.clock,
 	}
+
+	if cfg.sizer != nil {
+		sizer, ok := cfg.sizer.(Sizer[V])
+		if !ok {
+			panic(fmt.Sprintf("hw04lrucache: sizer %T does not match value type", cfg.sizer))
+		}
+		c.sizer = sizer
+	}
+
+	if cfg.janitorInterval > 0 {
+		c.stop = make(chan struct{})
+		c.stopped = make(chan struct{})
+		go c.janitor(cfg.janitorInterval)
+	}
+
+	return c
 }
 
 // Добавить значение в кэш.
-func (c *lruCache) Set(key Key, value interface{}) bool {
+// Значение, стоимость которого превышает бюджет кэша, не добавляется, а прежнее значение по ключу удаляется.
+func (c *lruCache[K, V]) Set(key K, value V) bool {
+	ok, _ := c.set(key, value, time.Time{}, c.costOf(value))
+	return ok
+}
+
+// Добавить значение в кэш с ограниченным временем жизни.
+// Значение ttl <= 0 означает бессрочное хранение.
+func (c *lruCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) bool {
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = c.clock.Now().Add(ttl)
+	}
+
+	ok, _ := c.set(key, value, expiresAt, c.costOf(value))
+	return ok
+}
+
+func (c *lruCache[K, V]) set(key K, value V, expiresAt time.Time, cost int64) (bool, error) {
+	if cost < 0 {
+		return false, fmt.Errorf("invalid cost %d", cost)
+	}
+
 	c.mtx.Lock()
-	defer c.mtx.Unlock()
+	defer c.unlockAndNotify()
 
 	itm, ok := c.items[key]
-	if ok { // ключ есть в кэше
-		cacheVal := itm.Value.(*cacheItem)
-		cacheVal.Value = value
-		itm.Value = cacheVal
-		c.queue.MoveToFront(itm)
-	} else { // ключа нет в кэше
-		if c.capacity == c.queue.Len() { // кэш полностью заполнен
-			excessVal := c.queue.Back().Value.(*cacheItem)
-			delete(c.items, excessVal.Key)
+	if ok && itm.Value.expired(c.clock.Now()) { // устаревший элемент считаем отсутствующим
+		c.remove(itm, EvictExpired)
+		ok = false
+	}
 
-			c.queue.Remove(c.queue.Back())
+	if c.budget > 0 && cost > c.budget {
+		// новое значение не помещается, а прежнее устарело и не должно возвращаться
+		if ok {
+			c.remove(itm, EvictRemoved)
 		}
+		return ok, fmt.Errorf("%w: cost %d, budget %d", ErrCostExceedsBudget, cost, c.budget)
+	}
 
-		cacheVal := &cacheItem{Key: key, Value: value}
-		itm = c.queue.PushFront(cacheVal)
+	if ok { // ключ есть в кэше
+		c.stats.updates.Add(1)
+		c.addEvicted(itm.Value, EvictReplaced) // прежнее значение может требовать освобождения ресурсов
+		c.cost += cost - itm.Value.cost
+		itm.Value.Value = value
+		itm.Value.expiresAt = expiresAt
+		itm.Value.cost = cost
+		c.queue.MoveToFront(itm)
+	} else { // ключа нет в кэше
+		c.stats.sets.Add(1)
+		c.cost += cost
+		itm = c.queue.PushFront(&cacheItem[K, V]{Key: key, Value: value, expiresAt: expiresAt, cost: cost})
 		c.items[key] = itm
 	}
 
-	return ok
+	// вытесняем элементы с конца очереди, пока кэш переполнен
+	for c.overflowed() && c.queue.Back() != itm {
+		c.remove(c.queue.Back(), EvictCapacity)
+	}
+
+	return ok, nil
 }
 
 // Получить значение из кэша.
-func (c *lruCache) Get(key Key) (interface{}, bool) {
-	defer c.mtx.Unlock()
+func (c *lruCache[K, V]) Get(key K) (V, bool) {
 	c.mtx.Lock()
+	defer c.unlockAndNotify()
 
 	itm, ok := c.items[key]
+	if ok && itm.Value.expired(c.clock.Now()) {
+		c.remove(itm, EvictExpired)
+		ok = false
+	}
 
 	if ok {
+		c.stats.hits.Add(1)
 		c.queue.MoveToFront(itm)
 
-		return itm.Value.(*cacheItem).Value, true
+		return itm.Value.Value, true
 	}
 
-	return nil, false
+	c.stats.misses.Add(1)
+	var zero V
+	return zero, false
+}
+
+// получить значение без учета в статистике и без изменения порядка вытеснения.
+func (c *lruCache[K, V]) peek(key K) (V, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	itm, ok := c.items[key]
+	if !ok || itm.Value.expired(c.clock.Now()) {
+		var zero V
+		return zero, false
+	}
+
+	return itm.Value.Value, true
 }
 
 // Очистить кэш.
-func (c *lruCache) Clear() {
+func (c *lruCache[K, V]) Clear() {
 	c.mtx.Lock()
-	c.queue = NewList()
-	c.items = make(map[Key]*ListItem, c.capacity)
-	c.mtx.Unlock()
+	defer c.unlockAndNotify()
+
+	if c.onEvict != nil {
+		now := c.clock.Now()
+		for ci := range c.queue.Backward() {
+			reason := EvictCleared
+			if ci.expired(now) {
+				reason = EvictExpired
+			}
+			c.addEvicted(ci, reason)
+		}
+	}
+
+	c.queue = NewTypedList[*cacheItem[K, V]]()
+	c.items = make(map[K]*TypedListItem[*cacheItem[K, V]], c.capacity)
+	c.cost = 0
+}
+
+// удалить элемент из кэша. Вызывается под блокировкой.
+func (c *lruCache[K, V]) remove(itm *TypedListItem[*cacheItem[K, V]], reason EvictReason) {
+	c.queue.Remove(itm)
+	c.forget(itm.Value, reason)
+}
+
+// учесть удаление элемента из очереди: удалить из словаря, обновить стоимость и статистику.
+// Вызывается под блокировкой.
+func (c *lruCache[K, V]) forget(ci *cacheItem[K, V], reason EvictReason) {
+	delete(c.items, ci.Key)
+	c.cost -= ci.cost
+
+	switch reason {
+	case EvictCapacity:
+		c.stats.evictions.Add(1)
+	case EvictExpired:
+		c.stats.expirations.Add(1)
+	}
+
+	c.addEvicted(ci, reason)
 }
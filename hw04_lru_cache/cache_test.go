@@ -10,8 +10,20 @@ import (
 )
 
 func TestCache(t *testing.T) {
+	t.Run("Cache", func(t *testing.T) {
+		testCache(t, NewCache, func(n int) interface{} { return n })
+	})
+	t.Run("TypedCache", func(t *testing.T) {
+		testCache(t, NewTypedCache[string, int], func(n int) int { return n })
+	})
+}
+
+// общие тесты кэша; val преобразует число в значение кэша.
+func testCache[K ~string, V any](t *testing.T, newCache func(capacity int) TypedCache[K, V], val func(n int) V) {
+	t.Helper()
+
 	t.Run("empty cache", func(t *testing.T) {
-		c := NewCache(10)
+		c := newCache(10)
 
 		_, ok := c.Get("aaa")
 		require.False(t, ok)
@@ -21,87 +33,136 @@ func TestCache(t *testing.T) {
 	})
 
 	t.Run("simple", func(t *testing.T) {
-		c := NewCache(5)
+		c := newCache(5)
 
-		wasInCache := c.Set("aaa", 100)
+		wasInCache := c.Set("aaa", val(100))
 		require.False(t, wasInCache)
 
-		wasInCache = c.Set("bbb", 200)
+		wasInCache = c.Set("bbb", val(200))
 		require.False(t, wasInCache)
 
-		val, ok := c.Get("aaa")
+		v, ok := c.Get("aaa")
 		require.True(t, ok)
-		require.Equal(t, 100, val)
+		require.Equal(t, val(100), v)
 
-		val, ok = c.Get("bbb")
+		v, ok = c.Get("bbb")
 		require.True(t, ok)
-		require.Equal(t, 200, val)
+		require.Equal(t, val(200), v)
 
-		wasInCache = c.Set("aaa", 300)
+		wasInCache = c.Set("aaa", val(300))
 		require.True(t, wasInCache)
 
-		val, ok = c.Get("aaa")
+		v, ok = c.Get("aaa")
 		require.True(t, ok)
-		require.Equal(t, 300, val)
+		require.Equal(t, val(300), v)
 
-		val, ok = c.Get("ccc")
+		v, ok = c.Get("ccc")
 		require.False(t, ok)
-		require.Nil(t, val)
+		require.Zero(t, v)
 	})
 
 	t.Run("purge logic", func(t *testing.T) {
-		c := NewCache(5)
-		c.Set("100", 100)
-		c.Set("200", 200)
+		c := newCache(5)
+		c.Set("100", val(100))
+		c.Set("200", val(200))
 		c.Clear()
 
-		val, ok := c.Get("100")
+		v, ok := c.Get("100")
 		require.False(t, ok)
-		require.Nil(t, val)
+		require.Zero(t, v)
 	})
 
 	t.Run("extrude", func(t *testing.T) {
-		c := NewCache(5)
-		c.Set("100", 100)
-		c.Set("200", 200)
-		c.Set("300", 300)
-		c.Set("400", 400)
-		c.Set("500", 500)
+		c := newCache(5)
+		c.Set("100", val(100))
+		c.Set("200", val(200))
+		c.Set("300", val(300))
+		c.Set("400", val(400))
+		c.Set("500", val(500))
 		// 500, 400, 300, 200, 100
 
-		val, ok := c.Get("100") // 100, 500, 400, 300, 200
+		v, ok := c.Get("100") // 100, 500, 400, 300, 200
+		require.True(t, ok)
+		require.Equal(t, val(100), v)
+
+		ok = c.Set("1000", val(1000)) // 1000, 100, 500, 400, 300
+		require.False(t, ok)
+
+		v, ok = c.Get("200")
+		require.False(t, ok)
+		require.Zero(t, v)
+
+		v, ok = c.Get("300") // 100, 500, 400, 300, 200
+		require.True(t, ok)
+		require.Equal(t, val(300), v)
+	})
+}
+
+func TestTypedCache(t *testing.T) {
+	t.Run("int keys", func(t *testing.T) {
+		c := NewTypedCache[int, int](3)
+		c.Set(100, 100)
+		c.Set(200, 200)
+		c.Set(300, 300)
+		// 300, 200, 100
+
+		_, ok := c.Get(100) // 100, 300, 200
 		require.True(t, ok)
-		require.Equal(t, 100, val)
 
-		ok = c.Set("1000", 1000) // 1000, 100, 500, 400, 300
+		c.Set(400, 400) // 400, 100, 300
+
+		val, ok := c.Get(200)
 		require.False(t, ok)
+		require.Zero(t, val)
 
-		val, ok = c.Get("200")
+		c.Clear()
+		_, ok = c.Get(100)
 		require.False(t, ok)
-		require.Nil(t, val)
+	})
 
-		val, ok = c.Get("300") // 100, 500, 400, 300, 200
+	t.Run("struct values", func(t *testing.T) {
+		type user struct {
+			Name string
+			Age  int
+		}
+		c := NewTypedCache[Key, user](2)
+		c.Set("u1", user{Name: "Ivan", Age: 30})
+
+		u, ok := c.Get("u1")
 		require.True(t, ok)
-		require.Equal(t, 300, val)
+		require.Equal(t, "Ivan", u.Name) // приведение типа не требуется
+	})
+}
+
+func TestCacheMultithreading(t *testing.T) {
+	t.Run("Cache", func(t *testing.T) {
+		testCacheMultithreading(t, NewCache, func(n int) interface{} { return n })
+	})
+	t.Run("TypedCache", func(t *testing.T) {
+		testCacheMultithreading(t, NewTypedCache[string, int], func(n int) int { return n })
 	})
 }
 
-func TestCacheMultithreading(_ *testing.T) {
-	c := NewCache(10)
+func testCacheMultithreading[K ~string, V any](
+	t *testing.T, newCache func(capacity int) TypedCache[K, V], val func(n int) V,
+) {
+	t.Helper()
+
+	c := newCache(10)
 	wg := &sync.WaitGroup{}
 	wg.Add(2)
 
 	go func() {
 		defer wg.Done()
 		for i := 0; i < 1_000_000; i++ {
-			c.Set(Key(strconv.Itoa(i)), i)
+			c.Set(K(strconv.Itoa(i)), val(i))
 		}
 	}()
 
 	go func() {
 		defer wg.Done()
 		for i := 0; i < 1_000_000; i++ {
-			c.Get(Key(strconv.Itoa(rand.Intn(1_000_000))))
+			c.Get(K(strconv.Itoa(rand.Intn(1_000_000))))
 		}
 	}()
 
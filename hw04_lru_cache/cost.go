@@ -0,0 +1,38 @@
+package hw04lrucache
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrCostExceedsBudget - стоимость значения превышает бюджет кэша.
+var ErrCostExceedsBudget = errors.New("cost exceeds cache budget")
+
+// Sizer - функция расчета стоимости значения.
+type Sizer[V any] func(value V) int64
+
+// Добавить значение в кэш с заданной стоимостью.
+// Значение, стоимость которого превышает бюджет кэша, не добавляется, возвращается ErrCostExceedsBudget;
+// прежнее значение по тому же ключу при этом удаляется.
+func (c *lruCache[K, V]) SetWithCost(key K, value V, cost int64) (bool, error) {
+	return c.set(key, value, time.Time{}, cost)
+}
+
+// стоимость значения. Без функции расчета стоимость каждого значения равна 1.
+// Отрицательная стоимость, рассчитанная функцией, отклоняется при добавлении, как в SetWithCost.
+func (c *lruCache[K, V]) costOf(value V) int64 {
+	if c.sizer == nil {
+		return 1
+	}
+
+	return c.sizer(value)
+}
+
+// кэш переполнен по количеству элементов или по стоимости. Вызывается под блокировкой.
+func (c *lruCache[K, V]) overflowed() bool {
+	if c.capacity > 0 && c.queue.Len() > c.capacity {
+		return true
+	}
+
+	return c.budget > 0 && c.cost > c.budget
+}
@@ -0,0 +1,133 @@
+package hw04lrucache
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheCost(t *testing.T) {
+	sizer := func(v []byte) int64 { return int64(len(v)) }
+
+	t.Run("evict by budget", func(t *testing.T) {
+		c := NewLRUCache[Key, []byte](0, WithCostBudget(10), WithSizer(sizer))
+
+		c.Set("aaa", make([]byte, 4))
+		c.Set("bbb", make([]byte, 4))
+		require.Equal(t, int64(8), c.Stats().Cost)
+
+		c.Set("ccc", make([]byte, 5)) // вытесняет aaa
+		require.Equal(t, int64(9), c.Stats().Cost)
+
+		_, ok := c.Get("aaa")
+		require.False(t, ok)
+
+		c.Set("ddd", make([]byte, 8)) // вытесняет bbb и ccc
+		require.Equal(t, Stats{Sets: 4, Evictions: 3, Misses: 1, Len: 1, Cost: 8}, c.Stats())
+	})
+
+	t.Run("update cost", func(t *testing.T) {
+		c := NewLRUCache[Key, []byte](0, WithCostBudget(10), WithSizer(sizer))
+
+		c.Set("aaa", make([]byte, 3))
+		c.Set("bbb", make([]byte, 3))
+		c.Set("aaa", make([]byte, 8)) // вытесняет bbb, сам aaa остается
+
+		_, ok := c.Get("bbb")
+		require.False(t, ok)
+
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Len(t, val, 8)
+		require.Equal(t, int64(8), c.Stats().Cost)
+	})
+
+	t.Run("set with cost", func(t *testing.T) {
+		c := NewLRUCache[Key, int](0, WithCostBudget(100))
+
+		ok, err := c.SetWithCost("aaa", 1, 60)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		ok, err = c.SetWithCost("bbb", 2, 50) // вытесняет aaa
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		_, ok = c.Get("aaa")
+		require.False(t, ok)
+
+		_, err = c.SetWithCost("ccc", 3, -1)
+		require.Error(t, err)
+	})
+
+	t.Run("reject oversized", func(t *testing.T) {
+		c := NewLRUCache[Key, []byte](0, WithCostBudget(10), WithSizer(sizer))
+		c.Set("aaa", make([]byte, 5))
+
+		wasInCache := c.Set("bbb", make([]byte, 11))
+		require.False(t, wasInCache)
+
+		_, err := c.SetWithCost("ccc", nil, 11)
+		require.True(t, errors.Is(err, ErrCostExceedsBudget))
+
+		_, ok := c.Get("bbb")
+		require.False(t, ok)
+
+		_, ok = c.Get("aaa") // содержимое кэша не изменилось
+		require.True(t, ok)
+		require.Equal(t, int64(5), c.Stats().Cost)
+	})
+
+	t.Run("oversized update", func(t *testing.T) {
+		c := NewLRUCache[Key, []byte](0, WithCostBudget(10), WithSizer(sizer))
+		var reasons []EvictReason
+		c.OnEvict(func(_ Key, _ []byte, reason EvictReason) {
+			reasons = append(reasons, reason)
+		})
+		c.Set("aaa", make([]byte, 5))
+
+		wasInCache := c.Set("aaa", make([]byte, 50))
+		require.True(t, wasInCache)
+		require.Equal(t, []EvictReason{EvictRemoved}, reasons)
+
+		_, ok := c.Get("aaa") // прежнее значение не возвращается
+		require.False(t, ok)
+		require.Zero(t, c.Stats().Cost)
+
+		c.Set("aaa", make([]byte, 5))
+		ok, err := c.SetWithCost("aaa", nil, 11)
+		require.True(t, ok)
+		require.True(t, errors.Is(err, ErrCostExceedsBudget))
+		require.Zero(t, c.Stats().Len)
+	})
+
+	t.Run("negative sizer", func(t *testing.T) {
+		c := NewLRUCache[Key, int](0, WithCostBudget(10), WithSizer(func(int) int64 { return -5 }))
+		for i := 0; i < 100; i++ {
+			c.Set(Key(strconv.Itoa(i)), i)
+		}
+
+		_, ok := c.Get("0")
+		require.False(t, ok)
+		require.Equal(t, Stats{Misses: 1}, c.Stats())
+	})
+
+	t.Run("capacity and budget", func(t *testing.T) {
+		c := NewLRUCache[Key, int](2, WithCostBudget(100))
+		c.SetWithCost("aaa", 1, 10)
+		c.SetWithCost("bbb", 2, 10)
+		c.SetWithCost("ccc", 3, 10) // вытесняет aaa по количеству
+
+		_, ok := c.Get("aaa")
+		require.False(t, ok)
+		require.Equal(t, int64(20), c.Stats().Cost)
+	})
+
+	t.Run("sizer type mismatch", func(t *testing.T) {
+		require.Panics(t, func() {
+			NewLRUCache[Key, int](10, WithSizer(sizer))
+		})
+	})
+}
@@ -0,0 +1,86 @@
+package hw04lrucache
+
+// EvictReason - причина удаления элемента из кэша.
+type EvictReason int
+
+const (
+	EvictCapacity EvictReason = iota // вытеснен при заполнении кэша
+	EvictRemoved                     // удален явно методом Remove
+	EvictCleared                     // удален при очистке кэша методом Clear
+	EvictExpired                     // истекло время жизни
+	EvictReplaced                    // значение заменено новым при добавлении по тому же ключу
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case EvictCapacity:
+		return "capacity"
+	case EvictRemoved:
+		return "removed"
+	case EvictCleared:
+		return "cleared"
+	case EvictExpired:
+		return "expired"
+	case EvictReplaced:
+		return "replaced"
+	default:
+		return "unknown"
+	}
+}
+
+// EvictFunc - функция, вызываемая при удалении элемента из кэша.
+type EvictFunc[K comparable, V any] func(key K, value V, reason EvictReason)
+
+// удаленный из кэша элемент, ожидающий уведомления.
+type eviction[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictReason
+}
+
+// Установить функцию, вызываемую при удалении элемента из кэша.
+// Функция вызывается вне блокировки кэша, поэтому может обращаться к кэшу.
+func (c *lruCache[K, V]) OnEvict(fn func(key K, value V, reason EvictReason)) {
+	c.mtx.Lock()
+	c.onEvict = fn
+	c.mtx.Unlock()
+}
+
+// Удалить значение из кэша.
+func (c *lruCache[K, V]) Remove(key K) bool {
+	c.mtx.Lock()
+	defer c.unlockAndNotify()
+
+	itm, ok := c.items[key]
+	if !ok {
+		return false
+	}
+
+	if itm.Value.expired(c.clock.Now()) {
+		c.remove(itm, EvictExpired)
+		return false
+	}
+
+	c.remove(itm, EvictRemoved)
+	return true
+}
+
+// запомнить удаленный элемент для уведомления. Вызывается под блокировкой.
+func (c *lruCache[K, V]) addEvicted(itm *cacheItem[K, V], reason EvictReason) {
+	if c.onEvict == nil {
+		return
+	}
+
+	c.evicted = append(c.evicted, eviction[K, V]{key: itm.Key, value: itm.Value, reason: reason})
+}
+
+// снять блокировку кэша и уведомить об удаленных элементах.
+func (c *lruCache[K, V]) unlockAndNotify() {
+	evicted, fn := c.evicted, c.onEvict
+	c.evicted = nil
+	c.mtx.Unlock()
+
+	for _, e := range evicted {
+		fn(e.key, e.value, e.reason)
+	}
+}
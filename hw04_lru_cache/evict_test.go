@@ -0,0 +1,127 @@
+package hw04lrucache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type evicted struct {
+	key    Key
+	value  interface{}
+	reason EvictReason
+}
+
+func TestCacheOnEvict(t *testing.T) {
+	newCache := func(capacity int, opts ...Option) (LRUCache[Key, interface{}], *[]evicted) {
+		c := NewLRUCache[Key, interface{}](capacity, opts...)
+		res := make([]evicted, 0)
+		c.OnEvict(func(key Key, value interface{}, reason EvictReason) {
+			res = append(res, evicted{key: key, value: value, reason: reason})
+		})
+		return c, &res
+	}
+
+	t.Run("capacity", func(t *testing.T) {
+		c, res := newCache(2)
+		c.Set("100", 100)
+		c.Set("200", 200)
+		c.Set("100", 101) // обновление не вытесняет другие элементы
+		require.Equal(t, []evicted{{key: "100", value: 100, reason: EvictReplaced}}, *res)
+		*res = (*res)[:0]
+
+		c.Set("300", 300)
+		require.Equal(t, []evicted{{key: "200", value: 200, reason: EvictCapacity}}, *res)
+	})
+
+	t.Run("replaced", func(t *testing.T) {
+		c, res := newCache(2)
+		c.Set("100", 100)
+		c.SetWithTTL("100", 101, time.Hour)
+		_, err := c.SetWithCost("100", 102, 1)
+		require.NoError(t, err)
+
+		require.Equal(t, []evicted{
+			{key: "100", value: 100, reason: EvictReplaced},
+			{key: "100", value: 101, reason: EvictReplaced},
+		}, *res)
+
+		val, ok := c.Get("100")
+		require.True(t, ok)
+		require.Equal(t, 102, val)
+	})
+
+	t.Run("removed", func(t *testing.T) {
+		c, res := newCache(2)
+		c.Set("100", 100)
+
+		require.True(t, c.Remove("100"))
+		require.False(t, c.Remove("100"))
+		require.Equal(t, []evicted{{key: "100", value: 100, reason: EvictRemoved}}, *res)
+
+		_, ok := c.Get("100")
+		require.False(t, ok)
+	})
+
+	t.Run("cleared", func(t *testing.T) {
+		c, res := newCache(5)
+		c.Set("100", 100)
+		c.Set("200", 200)
+		c.Clear()
+
+		require.Equal(t, []evicted{
+			{key: "100", value: 100, reason: EvictCleared},
+			{key: "200", value: 200, reason: EvictCleared},
+		}, *res)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		clock := newFakeClock()
+		c, res := newCache(5, WithClock(clock))
+		c.SetWithTTL("100", 100, time.Second)
+		c.SetWithTTL("200", 200, time.Second)
+		c.SetWithTTL("300", 300, time.Second)
+		clock.Advance(time.Second)
+
+		c.Get("100")
+		c.Set("200", 201)
+		c.DeleteExpired()
+
+		require.Equal(t, []evicted{
+			{key: "100", value: 100, reason: EvictExpired},
+			{key: "200", value: 200, reason: EvictExpired},
+			{key: "300", value: 300, reason: EvictExpired},
+		}, *res)
+	})
+
+	t.Run("reentrant callback", func(t *testing.T) {
+		c := NewLRUCache[Key, interface{}](2)
+
+		c.OnEvict(func(key Key, value interface{}, _ EvictReason) {
+			// обращение к кэшу из функции уведомления не должно приводить к взаимной блокировке
+			_, ok := c.Get(key)
+			require.False(t, ok)
+			if key == "100" {
+				c.Set("evicted", value)
+			}
+		})
+
+		c.Set("100", 100)
+		c.Set("200", 200)
+		c.Set("300", 300) // вытесняет 100, который возвращается под ключом evicted и вытесняет 200
+
+		val, ok := c.Get("evicted")
+		require.True(t, ok)
+		require.Equal(t, 100, val)
+	})
+}
+
+func TestEvictReasonString(t *testing.T) {
+	require.Equal(t, "capacity", EvictCapacity.String())
+	require.Equal(t, "removed", EvictRemoved.String())
+	require.Equal(t, "cleared", EvictCleared.String())
+	require.Equal(t, "expired", EvictExpired.String())
+	require.Equal(t, "replaced", EvictReplaced.String())
+	require.Equal(t, "unknown", EvictReason(100).String())
+}
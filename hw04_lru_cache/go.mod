@@ -1,6 +1,6 @@
 module github.com/DimVlas/otus_hw/hw04_lru_cache
 
-go 1.22
+go 1.23
 
 require github.com/stretchr/testify v1.7.0
 
@@ -0,0 +1,116 @@
+package hw04lrucache
+
+import "sync"
+
+// элемент LFU-кэша.
+type lfuItem[K comparable, V any] struct {
+	Value V
+	Key   K
+	freq  int // количество обращений к элементу
+}
+
+// LFU-кэш: вытесняется элемент с наименьшим числом обращений,
+// среди равных по числу обращений - давнее всех использованный.
+// Для каждого числа обращений ведется своя очередь, поэтому все операции O(1).
+type lfuCache[K comparable, V any] struct {
+	capacity int
+	mtx      sync.Mutex
+	items    map[K]*TypedListItem[*lfuItem[K, V]]
+	freqs    map[int]TypedList[*lfuItem[K, V]] // очереди элементов по числу обращений
+	minFreq  int                               // наименьшее число обращений среди элементов
+}
+
+// Создать новый LFU-кэш.
+func NewLFUCache(capacity int) Cache {
+	return NewTypedLFUCache[Key, interface{}](capacity)
+}
+
+// Создать новый LFU-кэш с ключами типа K и значениями типа V.
+func NewTypedLFUCache[K comparable, V any](capacity int) TypedCache[K, V] {
+	return &lfuCache[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*TypedListItem[*lfuItem[K, V]], capacity),
+		freqs:    make(map[int]TypedList[*lfuItem[K, V]]),
+	}
+}
+
+// Добавить значение в кэш.
+func (c *lfuCache[K, V]) Set(key K, value V) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if itm, ok := c.items[key]; ok {
+		itm.Value.Value = value
+		c.touch(itm)
+		return true
+	}
+
+	if c.capacity > 0 && len(c.items) >= c.capacity { // кэш полностью заполнен
+		q := c.freqs[c.minFreq]
+		excess := q.Back()
+		delete(c.items, excess.Value.Key)
+		c.removeFromFreq(excess)
+	}
+
+	c.minFreq = 1
+	c.items[key] = c.queue(1).PushFront(&lfuItem[K, V]{Key: key, Value: value, freq: 1})
+
+	return false
+}
+
+// Получить значение из кэша.
+func (c *lfuCache[K, V]) Get(key K) (V, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	itm, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	c.touch(itm)
+	return itm.Value.Value, true
+}
+
+// Очистить кэш.
+func (c *lfuCache[K, V]) Clear() {
+	c.mtx.Lock()
+	c.items = make(map[K]*TypedListItem[*lfuItem[K, V]], c.capacity)
+	c.freqs = make(map[int]TypedList[*lfuItem[K, V]])
+	c.minFreq = 0
+	c.mtx.Unlock()
+}
+
+// учесть обращение к элементу: перенести его в очередь со следующим числом обращений.
+// Элемент списка пересоздается, поэтому индекс по ключу обновляется.
+func (c *lfuCache[K, V]) touch(itm *TypedListItem[*lfuItem[K, V]]) {
+	freq := itm.Value.freq
+	c.removeFromFreq(itm)
+	if c.minFreq == freq && c.freqs[freq] == nil {
+		c.minFreq++
+	}
+
+	itm.Value.freq++
+	c.items[itm.Value.Key] = c.queue(itm.Value.freq).PushFront(itm.Value)
+}
+
+// удалить элемент из очереди его числа обращений, пустая очередь удаляется.
+func (c *lfuCache[K, V]) removeFromFreq(itm *TypedListItem[*lfuItem[K, V]]) {
+	q := c.freqs[itm.Value.freq]
+	q.Remove(itm)
+	if q.Len() == 0 {
+		delete(c.freqs, itm.Value.freq)
+	}
+}
+
+// очередь элементов с числом обращений freq.
+func (c *lfuCache[K, V]) queue(freq int) TypedList[*lfuItem[K, V]] {
+	q, ok := c.freqs[freq]
+	if !ok {
+		q = NewTypedList[*lfuItem[K, V]]()
+		c.freqs[freq] = q
+	}
+
+	return q
+}
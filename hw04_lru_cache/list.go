@@ -1,26 +1,219 @@
 package hw04lrucache
 
-type List interface {
-	Len() int
-	Front() *ListItem
-	Back() *ListItem
-	PushFront(v interface{}) *ListItem
-	PushBack(v interface{}) *ListItem
-	Remove(i *ListItem)
-	MoveToFront(i *ListItem)
+import "iter"
+
+// List - двусвязный список значений произвольного типа.
+type List = TypedList[interface{}]
+
+// ListItem - элемент списка List.
+type ListItem = TypedListItem[interface{}]
+
+// TypedList - двусвязный список значений типа T.
+type TypedList[T any] interface {
+	Len() int                        // длина списка
+	Front() *TypedListItem[T]        // первый элемент списка
+	Back() *TypedListItem[T]         // последний элемент списка
+	PushFront(v T) *TypedListItem[T] // добавить значение в начало
+	PushBack(v T) *TypedListItem[T]  // добавить значение в конец
+	Remove(i *TypedListItem[T])      // удалить элемент
+	MoveToFront(i *TypedListItem[T]) // переместить элемент в начало
+
+	MoveToBack(i *TypedListItem[T])                             // переместить элемент в конец
+	InsertBefore(v T, mark *TypedListItem[T]) *TypedListItem[T] // вставить значение перед элементом mark
+	InsertAfter(v T, mark *TypedListItem[T]) *TypedListItem[T]  // вставить значение после элемента mark
+	RemoveIf(pred func(v T) bool) int                           // удалить элементы, удовлетворяющие условию
+	PushBackList(other TypedList[T])                            // добавить в конец копии значений списка other
+	All() iter.Seq[T]                                           // значения от начала к концу
+	Backward() iter.Seq[T]                                      // значения от конца к началу
 }
 
-type ListItem struct {
-	Value interface{}
-	Next  *ListItem
-	Prev  *ListItem
+// TypedListItem - элемент списка TypedList.
+type TypedListItem[T any] struct {
+	Value T                 // значение
+	Next  *TypedListItem[T] // следующий элемент
+	Prev  *TypedListItem[T] // предыдущий элемент
 }
 
-type list struct {
-	List // Remove me after realization.
-	// Place your code here.
+type list[T any] struct {
+	len   int
+	front *TypedListItem[T]
+	back  *TypedListItem[T]
 }
 
+// Создать новый список значений произвольного типа.
 func NewList() List {
-	return new(list)
+	return NewTypedList[interface{}]()
+}
+
+// Создать новый список значений типа T.
+func NewTypedList[T any]() TypedList[T] {
+	return new(list[T])
+}
+
+func (l *list[T]) Len() int {
+	return l.len
+}
+
+func (l *list[T]) Front() *TypedListItem[T] {
+	return l.front
+}
+
+func (l *list[T]) Back() *TypedListItem[T] {
+	return l.back
+}
+
+func (l *list[T]) PushFront(v T) *TypedListItem[T] {
+	itm := &TypedListItem[T]{Value: v}
+	l.linkFront(itm)
+	l.len++
+
+	return itm
+}
+
+func (l *list[T]) PushBack(v T) *TypedListItem[T] {
+	itm := &TypedListItem[T]{Value: v}
+	l.linkBack(itm)
+	l.len++
+
+	return itm
+}
+
+func (l *list[T]) Remove(i *TypedListItem[T]) {
+	l.unlink(i)
+	l.len--
+}
+
+func (l *list[T]) MoveToFront(i *TypedListItem[T]) {
+	if l.front == i {
+		return
+	}
+
+	l.unlink(i)
+	l.linkFront(i)
+}
+
+func (l *list[T]) MoveToBack(i *TypedListItem[T]) {
+	if l.back == i {
+		return
+	}
+
+	l.unlink(i)
+	l.linkBack(i)
+}
+
+func (l *list[T]) InsertBefore(v T, mark *TypedListItem[T]) *TypedListItem[T] {
+	if mark.Prev == nil {
+		return l.PushFront(v)
+	}
+
+	return l.InsertAfter(v, mark.Prev)
+}
+
+func (l *list[T]) InsertAfter(v T, mark *TypedListItem[T]) *TypedListItem[T] {
+	if mark.Next == nil {
+		return l.PushBack(v)
+	}
+
+	itm := &TypedListItem[T]{Value: v, Prev: mark, Next: mark.Next}
+	mark.Next.Prev = itm
+	mark.Next = itm
+	l.len++
+
+	return itm
+}
+
+// Удалить элементы, удовлетворяющие условию pred, возвращает количество удаленных.
+// Элементы проверяются от начала к концу списка.
+func (l *list[T]) RemoveIf(pred func(v T) bool) int {
+	removed := 0
+	for i := l.front; i != nil; {
+		next := i.Next
+		if pred(i.Value) {
+			l.Remove(i)
+			removed++
+		}
+		i = next
+	}
+
+	return removed
+}
+
+// Добавить в конец списка копии значений списка other. Список other не изменяется.
+// Допускается передача самого списка: его значения будут продублированы.
+func (l *list[T]) PushBackList(other TypedList[T]) {
+	for i, n := other.Front(), other.Len(); n > 0; i, n = i.Next, n-1 {
+		l.PushBack(i.Value)
+	}
+}
+
+// Значения от начала к концу списка.
+// Удаление текущего элемента во время обхода допустимо.
+func (l *list[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := l.front; i != nil; {
+			next := i.Next
+			if !yield(i.Value) {
+				return
+			}
+			i = next
+		}
+	}
+}
+
+// Значения от конца к началу списка.
+// Удаление текущего элемента во время обхода допустимо.
+func (l *list[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := l.back; i != nil; {
+			prev := i.Prev
+			if !yield(i.Value) {
+				return
+			}
+			i = prev
+		}
+	}
+}
+
+// вставить элемент в начало списка.
+func (l *list[T]) linkFront(i *TypedListItem[T]) {
+	i.Prev = nil
+	i.Next = l.front
+
+	if l.front != nil {
+		l.front.Prev = i
+	} else { // список пуст
+		l.back = i
+	}
+	l.front = i
+}
+
+// вставить элемент в конец списка.
+func (l *list[T]) linkBack(i *TypedListItem[T]) {
+	i.Next = nil
+	i.Prev = l.back
+
+	if l.back != nil {
+		l.back.Next = i
+	} else { // список пуст
+		l.front = i
+	}
+	l.back = i
+}
+
+// исключить элемент из цепочки, не меняя длину списка.
+func (l *list[T]) unlink(i *TypedListItem[T]) {
+	if i.Prev != nil {
+		i.Prev.Next = i.Next
+	} else { // первый элемент
+		l.front = i.Next
+	}
+
+	if i.Next != nil {
+		i.Next.Prev = i.Prev
+	} else { // последний элемент
+		l.back = i.Prev
+	}
+
+	i.Next = nil
+	i.Prev = nil
 }
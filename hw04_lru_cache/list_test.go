@@ -1,14 +1,35 @@
 package hw04lrucache
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 )
 
 func TestList(t *testing.T) {
+	t.Run("List", func(t *testing.T) {
+		testList(t, NewList, func(n int) interface{} { return n })
+	})
+	t.Run("TypedList", func(t *testing.T) {
+		testList(t, NewTypedList[int], func(n int) int { return n })
+	})
+}
+
+// общие тесты списка; val преобразует число в значение элемента списка.
+func testList[T any](t *testing.T, newList func() TypedList[T], val func(n int) T) {
+	t.Helper()
+
+	vals := func(ns ...int) []T {
+		res := make([]T, len(ns))
+		for i, n := range ns {
+			res[i] = val(n)
+		}
+		return res
+	}
+
 	t.Run("empty list", func(t *testing.T) {
-		l := NewList()
+		l := newList()
 
 		require.Equal(t, 0, l.Len())
 		require.Nil(t, l.Front())
@@ -16,9 +37,9 @@ func TestList(t *testing.T) {
 	})
 
 	t.Run("single front", func(t *testing.T) {
-		l := NewList()
+		l := newList()
 
-		const testVal string = "TestValue"
+		testVal := val(7)
 		l.PushFront(testVal)
 		first := l.Front()
 		last := l.Back()
@@ -34,9 +55,9 @@ func TestList(t *testing.T) {
 	})
 
 	t.Run("single back", func(t *testing.T) {
-		l := NewList()
+		l := newList()
 
-		const testVal string = "TestValue"
+		testVal := val(7)
 		l.PushBack(testVal)
 		first := l.Front()
 		last := l.Back()
@@ -52,61 +73,167 @@ func TestList(t *testing.T) {
 	})
 
 	t.Run("move to front", func(t *testing.T) {
-		l := NewList()
+		l := newList()
 
-		l.PushBack(11)
-		l.PushBack(22)
-		l.PushBack(33)
+		l.PushBack(val(11))
+		l.PushBack(val(22))
+		l.PushBack(val(33))
 		require.Equal(t, 3, l.Len())
 
-		require.Equal(t, 11, l.Front().Value)
-		require.Equal(t, 22, l.Back().Prev.Value)
-		require.Equal(t, 33, l.Back().Value)
+		require.Equal(t, val(11), l.Front().Value)
+		require.Equal(t, val(22), l.Back().Prev.Value)
+		require.Equal(t, val(33), l.Back().Value)
 
 		l.MoveToFront(l.Front().Next)
 		l.MoveToFront(l.Back())
 
-		require.Equal(t, 33, l.Front().Value)
-		require.Equal(t, 22, l.Front().Next.Value)
-		require.Equal(t, 11, l.Back().Value)
+		require.Equal(t, val(33), l.Front().Value)
+		require.Equal(t, val(22), l.Front().Next.Value)
+		require.Equal(t, val(11), l.Back().Value)
 	})
 
 	t.Run("complex", func(t *testing.T) {
-		l := NewList()
+		l := newList()
 
-		l.PushFront(10) // [10]
-		l.PushBack(20)  // [10, 20]
-		l.PushBack(30)  // [10, 20, 30]
+		l.PushFront(val(10)) // [10]
+		l.PushBack(val(20))  // [10, 20]
+		l.PushBack(val(30))  // [10, 20, 30]
 
 		require.Equal(t, 3, l.Len())
 
 		middle := l.Front().Next // 20
-		require.Equal(t, 20, middle.Value)
+		require.Equal(t, val(20), middle.Value)
 
 		l.Remove(middle) // [10, 30]
 		require.Equal(t, 2, l.Len())
-		require.Equal(t, 10, l.Front().Value)
-		require.Equal(t, 30, l.Back().Value)
+		require.Equal(t, val(10), l.Front().Value)
+		require.Equal(t, val(30), l.Back().Value)
 
 		for i, v := range [...]int{40, 50, 60, 70, 80} {
 			if i%2 == 0 {
-				l.PushFront(v)
+				l.PushFront(val(v))
 			} else {
-				l.PushBack(v)
+				l.PushBack(val(v))
 			}
 		} // [80, 60, 40, 10, 30, 50, 70]
 
 		require.Equal(t, 7, l.Len())
-		require.Equal(t, 80, l.Front().Value)
-		require.Equal(t, 70, l.Back().Value)
+		require.Equal(t, val(80), l.Front().Value)
+		require.Equal(t, val(70), l.Back().Value)
 
 		l.MoveToFront(l.Front()) // [80, 60, 40, 10, 30, 50, 70]
 		l.MoveToFront(l.Back())  // [70, 80, 60, 40, 10, 30, 50]
 
-		elems := make([]int, 0, l.Len())
+		elems := make([]T, 0, l.Len())
 		for i := l.Front(); i != nil; i = i.Next {
-			elems = append(elems, i.Value.(int))
+			elems = append(elems, i.Value)
+		}
+		require.Equal(t, vals(70, 80, 60, 40, 10, 30, 50), elems)
+
+		elems = elems[:0]
+		for i := l.Back(); i != nil; i = i.Prev {
+			elems = append(elems, i.Value)
+		}
+		require.Equal(t, vals(50, 30, 10, 40, 60, 80, 70), elems)
+	})
+
+	t.Run("move to back", func(t *testing.T) {
+		l := newList()
+
+		l.PushBack(val(11))
+		l.PushBack(val(22))
+		l.PushBack(val(33))
+
+		l.MoveToBack(l.Front())      // [22, 33, 11]
+		l.MoveToBack(l.Front().Next) // [22, 11, 33]
+		l.MoveToBack(l.Back())       // [22, 11, 33]
+
+		require.Equal(t, vals(22, 11, 33), slices.Collect(l.All()))
+		require.Equal(t, 3, l.Len())
+	})
+
+	t.Run("insert", func(t *testing.T) {
+		l := newList()
+
+		mid := l.PushBack(val(20))        // [20]
+		l.InsertBefore(val(10), mid)      // [10, 20]
+		l.InsertAfter(val(30), mid)       // [10, 20, 30]
+		l.InsertBefore(val(5), l.Front()) // [5, 10, 20, 30]
+		l.InsertAfter(val(40), l.Back())  // [5, 10, 20, 30, 40]
+		l.InsertBefore(val(15), mid)      // [5, 10, 15, 20, 30, 40]
+
+		require.Equal(t, 6, l.Len())
+		require.Equal(t, vals(5, 10, 15, 20, 30, 40), slices.Collect(l.All()))
+		require.Equal(t, vals(40, 30, 20, 15, 10, 5), slices.Collect(l.Backward()))
+	})
+}
+
+func TestTypedList(t *testing.T) {
+	t.Run("iterators", func(t *testing.T) {
+		l := NewTypedList[int]()
+		require.Empty(t, slices.Collect(l.All()))
+		require.Empty(t, slices.Collect(l.Backward()))
+
+		for i := 1; i <= 5; i++ {
+			l.PushBack(i)
+		}
+
+		elems := make([]int, 0, 2)
+		for v := range l.All() {
+			if v > 2 {
+				break
+			}
+			elems = append(elems, v)
+		}
+		require.Equal(t, []int{1, 2}, elems)
+
+		elems = elems[:0]
+		for v := range l.Backward() {
+			if v < 4 {
+				break
+			}
+			elems = append(elems, v)
+		}
+		require.Equal(t, []int{5, 4}, elems)
+	})
+
+	t.Run("remove if", func(t *testing.T) {
+		l := NewTypedList[int]()
+		for i := 1; i <= 10; i++ {
+			l.PushBack(i)
 		}
-		require.Equal(t, []int{70, 80, 60, 40, 10, 30, 50}, elems)
+
+		removed := l.RemoveIf(func(v int) bool { return v%2 == 0 })
+		require.Equal(t, 5, removed)
+		require.Equal(t, 5, l.Len())
+		require.Equal(t, []int{1, 3, 5, 7, 9}, slices.Collect(l.All()))
+		require.Equal(t, []int{9, 7, 5, 3, 1}, slices.Collect(l.Backward()))
+
+		removed = l.RemoveIf(func(int) bool { return true })
+		require.Equal(t, 5, removed)
+		require.Equal(t, 0, l.Len())
+		require.Nil(t, l.Front())
+		require.Nil(t, l.Back())
+	})
+
+	t.Run("push back list", func(t *testing.T) {
+		l := NewTypedList[int]()
+		l.PushBack(1)
+		l.PushBack(2)
+
+		other := NewTypedList[int]()
+		other.PushBack(3)
+		other.PushBack(4)
+
+		l.PushBackList(other)
+		require.Equal(t, []int{1, 2, 3, 4}, slices.Collect(l.All()))
+		require.Equal(t, []int{3, 4}, slices.Collect(other.All()))
+
+		l.PushBackList(l)
+		require.Equal(t, []int{1, 2, 3, 4, 1, 2, 3, 4}, slices.Collect(l.All()))
+		require.Equal(t, 8, l.Len())
+
+		l.PushBackList(NewTypedList[int]())
+		require.Equal(t, 8, l.Len())
 	})
 }
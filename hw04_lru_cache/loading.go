@@ -0,0 +1,151 @@
+package hw04lrucache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Loader - функция загрузки значения, отсутствующего в кэше.
+type Loader = TypedLoader[Key, interface{}]
+
+// TypedLoader - функция загрузки значения типа V по ключу типа K.
+type TypedLoader[K comparable, V any] func(ctx context.Context, key K) (V, error)
+
+// LoadingCache - кэш, загружающий отсутствующие значения.
+type LoadingCache = TypedLoadingCache[Key, interface{}]
+
+// TypedLoadingCache - кэш с ключами типа K и значениями типа V, загружающий отсутствующие значения.
+type TypedLoadingCache[K comparable, V any] interface {
+	TypedCache[K, V]
+	Load(ctx context.Context, key K) (V, error) // Получить значение из кэша, при отсутствии загрузить.
+	Close()                                     // Остановить фоновую очистку кэша.
+}
+
+// выполняющаяся загрузка значения.
+type loadCall[V any] struct {
+	done  chan struct{} // закрывается по завершении загрузки
+	value V
+	err   error
+}
+
+type loadingCache[K comparable, V any] struct {
+	*lruCache[K, V]
+	loader TypedLoader[K, V]
+
+	errorTTL time.Duration
+	errs     *lruCache[K, error] // ошибки загрузки, хранятся errorTTL
+
+	callsMtx sync.Mutex
+	inflight map[K]*loadCall[V] // выполняющиеся загрузки
+}
+
+// Создать новый LRU-кэш, загружающий отсутствующие значения функцией loader.
+// Одновременные загрузки одного ключа объединяются в одну.
+func NewLoadingCache(capacity int, loader Loader, opts ...Option) LoadingCache {
+	return NewTypedLoadingCache[Key, interface{}](capacity, loader, opts...)
+}
+
+// Создать новый LRU-кэш с ключами типа K и значениями типа V, загружающий отсутствующие значения.
+func NewTypedLoadingCache[K comparable, V any](
+	capacity int, loader TypedLoader[K, V], opts ...Option,
+) TypedLoadingCache[K, V] {
+	cfg := newConfig(opts)
+
+	c := &loadingCache[K, V]{
+		lruCache: newLRUCache[K, V](capacity, opts...),
+		loader:   loader,
+		errorTTL: cfg.errorTTL,
+		inflight: make(map[K]*loadCall[V]),
+	}
+	if c.errorTTL > 0 {
+		c.errs = newLRUCache[K, error](capacity, WithClock(cfg.clock))
+	}
+
+	return c
+}
+
+// Получить значение из кэша, при отсутствии загрузить.
+// Загрузка не прерывается отменой ctx вызывающего, так как ее результат может ожидаться другими.
+func (c *loadingCache[K, V]) Load(ctx context.Context, key K) (V, error) {
+	if val, ok := c.Get(key); ok {
+		return val, nil
+	}
+
+	var zero V
+	if c.errs != nil {
+		if err, ok := c.errs.Get(key); ok {
+			return zero, err
+		}
+	}
+
+	c.callsMtx.Lock()
+	call, ok := c.inflight[key]
+	if !ok {
+		// загрузка могла завершиться после проверки выше: ее результат сохраняется до удаления из inflight
+		if val, found, err := c.stored(key); found {
+			c.callsMtx.Unlock()
+			return val, err
+		}
+
+		call = &loadCall[V]{done: make(chan struct{})}
+		c.inflight[key] = call
+		go c.load(context.WithoutCancel(ctx), key, call)
+	}
+	c.callsMtx.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case <-call.done:
+		return call.value, call.err
+	}
+}
+
+// результат завершившейся загрузки: значение в кэше или сохраненная ошибка.
+// Статистика и порядок вытеснения не изменяются.
+func (c *loadingCache[K, V]) stored(key K) (val V, found bool, err error) {
+	if val, ok := c.peek(key); ok {
+		return val, true, nil
+	}
+	if c.errs != nil {
+		if err, ok := c.errs.peek(key); ok {
+			return val, true, err
+		}
+	}
+
+	return val, false, nil
+}
+
+// Очистить кэш.
+func (c *loadingCache[K, V]) Clear() {
+	c.lruCache.Clear()
+	if c.errs != nil {
+		c.errs.Clear()
+	}
+}
+
+// загрузить значение и сохранить результат.
+func (c *loadingCache[K, V]) load(ctx context.Context, key K, call *loadCall[V]) {
+	defer func() {
+		if r := recover(); r != nil {
+			call.err = fmt.Errorf("loader panic: %v", r)
+		}
+
+		switch {
+		case call.err == nil:
+			c.Set(key, call.value)
+		case c.errs != nil:
+			c.errs.SetWithTTL(key, call.err, c.errorTTL)
+		}
+
+		c.callsMtx.Lock()
+		delete(c.inflight, key)
+		c.callsMtx.Unlock()
+
+		close(call.done)
+	}()
+
+	call.value, call.err = c.loader(ctx, key)
+}
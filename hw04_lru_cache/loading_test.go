@@ -0,0 +1,195 @@
+package hw04lrucache
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadingCache(t *testing.T) {
+	t.Run("load on miss", func(t *testing.T) {
+		var calls int32
+		c := NewLoadingCache(5, func(_ context.Context, key Key) (interface{}, error) {
+			atomic.AddInt32(&calls, 1)
+			return "value-" + string(key), nil
+		})
+
+		val, err := c.Load(context.Background(), "aaa")
+		require.NoError(t, err)
+		require.Equal(t, "value-aaa", val)
+
+		val, err = c.Load(context.Background(), "aaa") // значение уже в кэше
+		require.NoError(t, err)
+		require.Equal(t, "value-aaa", val)
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, "value-aaa", val)
+	})
+
+	t.Run("deduplication", func(t *testing.T) {
+		var calls int32
+		release := make(chan struct{})
+		c := NewTypedLoadingCache[string, int](5, func(_ context.Context, key string) (int, error) {
+			atomic.AddInt32(&calls, 1)
+			<-release
+			return strconv.Atoi(key)
+		})
+
+		const goroutines = 50
+		wg := sync.WaitGroup{}
+		results := make([]int, goroutines)
+		for i := 0; i < goroutines; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				val, err := c.Load(context.Background(), "42")
+				require.NoError(t, err)
+				results[i] = val
+			}(i)
+		}
+
+		time.Sleep(50 * time.Millisecond) // даем всем горутинам дождаться загрузки
+		close(release)
+		wg.Wait()
+
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+		for _, r := range results {
+			require.Equal(t, 42, r)
+		}
+	})
+
+	t.Run("deduplication after completed load", func(t *testing.T) {
+		// загрузка может завершиться между промахом кэша и регистрацией новой загрузки;
+		// окно воспроизводится только при параллельном выполнении, в том числе на одном процессоре
+		defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(8))
+
+		var calls int32
+		c := NewTypedLoadingCache[int, int](0, func(_ context.Context, key int) (int, error) {
+			atomic.AddInt32(&calls, 1)
+			return key, nil
+		})
+
+		const (
+			rounds     = 2000
+			goroutines = 8
+		)
+		for key := 0; key < rounds; key++ {
+			start := make(chan struct{})
+			wg := sync.WaitGroup{}
+			for i := 0; i < goroutines; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+					val, err := c.Load(context.Background(), key)
+					require.NoError(t, err)
+					require.Equal(t, key, val)
+				}()
+			}
+			close(start)
+			wg.Wait()
+		}
+
+		require.Equal(t, int32(rounds), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("errors without caching", func(t *testing.T) {
+		var calls int32
+		errLoad := errors.New("load error")
+		c := NewLoadingCache(5, func(_ context.Context, _ Key) (interface{}, error) {
+			atomic.AddInt32(&calls, 1)
+			return nil, errLoad
+		})
+
+		_, err := c.Load(context.Background(), "aaa")
+		require.ErrorIs(t, err, errLoad)
+		_, err = c.Load(context.Background(), "aaa")
+		require.ErrorIs(t, err, errLoad)
+
+		require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+		_, ok := c.Get("aaa")
+		require.False(t, ok)
+	})
+
+	t.Run("cached errors", func(t *testing.T) {
+		var calls int32
+		errLoad := errors.New("load error")
+		clock := newFakeClock()
+		c := NewLoadingCache(5, func(_ context.Context, _ Key) (interface{}, error) {
+			if atomic.AddInt32(&calls, 1) > 1 {
+				return 100, nil
+			}
+			return nil, errLoad
+		}, WithClock(clock), WithErrorTTL(time.Second))
+
+		_, err := c.Load(context.Background(), "aaa")
+		require.ErrorIs(t, err, errLoad)
+		_, err = c.Load(context.Background(), "aaa") // ошибка из кэша
+		require.ErrorIs(t, err, errLoad)
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+		clock.Advance(time.Second)
+
+		val, err := c.Load(context.Background(), "aaa")
+		require.NoError(t, err)
+		require.Equal(t, 100, val)
+		require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("caller cancel", func(t *testing.T) {
+		release := make(chan struct{})
+		c := NewLoadingCache(5, func(ctx context.Context, _ Key) (interface{}, error) {
+			<-release
+			return 100, ctx.Err()
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := c.Load(ctx, "aaa")
+		require.ErrorIs(t, err, context.Canceled)
+
+		close(release)
+
+		// отмена вызывающего не прерывает загрузку для остальных
+		val, err := c.Load(context.Background(), "aaa")
+		require.NoError(t, err)
+		require.Equal(t, 100, val)
+	})
+
+	t.Run("loader panic", func(t *testing.T) {
+		c := NewLoadingCache(5, func(_ context.Context, _ Key) (interface{}, error) {
+			panic("boom")
+		})
+
+		_, err := c.Load(context.Background(), "aaa")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "boom")
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		c := NewLoadingCache(5, func(_ context.Context, _ Key) (interface{}, error) {
+			return nil, errors.New("load error")
+		}, WithErrorTTL(time.Hour))
+
+		_, err := c.Load(context.Background(), "aaa")
+		require.Error(t, err)
+
+		c.Clear()
+		c.Set("aaa", 100)
+
+		val, err := c.Load(context.Background(), "aaa")
+		require.NoError(t, err)
+		require.Equal(t, 100, val)
+	})
+}
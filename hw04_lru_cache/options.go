@@ -0,0 +1,90 @@
+package hw04lrucache
+
+import "time"
+
+// Clock - источник текущего времени для кэша.
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// Option - параметр создания кэша.
+type Option func(*config)
+
+type config struct {
+	clock           Clock         // источник текущего времени
+	janitorInterval time.Duration // период фоновой очистки устаревших элементов
+	budget          int64         // максимальная суммарная стоимость элементов
+	sizer           interface{}   // функция расчета стоимости значения, Sizer[V]
+	errorTTL        time.Duration // время хранения ошибок загрузки
+	codec           Codec         // формат снимка кэша
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		clock: realClock{},
+		codec: GobCodec{},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// WithClock задает источник текущего времени.
+func WithClock(clock Clock) Option {
+	return func(c *config) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
+
+// WithJanitor запускает фоновую очистку устаревших элементов с периодом interval.
+// Фоновая очистка останавливается методом Close.
+func WithJanitor(interval time.Duration) Option {
+	return func(c *config) {
+		c.janitorInterval = interval
+	}
+}
+
+// WithCostBudget ограничивает суммарную стоимость элементов кэша.
+// При превышении бюджета элементы вытесняются с конца очереди.
+func WithCostBudget(budget int64) Option {
+	return func(c *config) {
+		c.budget = budget
+	}
+}
+
+// WithSizer задает функцию расчета стоимости значения.
+// Тип значения V должен совпадать с типом значений кэша.
+func WithSizer[V any](sizer Sizer[V]) Option {
+	return func(c *config) {
+		if sizer != nil {
+			c.sizer = sizer
+		}
+	}
+}
+
+// WithErrorTTL задает время, в течение которого ошибка загрузки значения
+// возвращается без повторного вызова загрузчика. Используется LoadingCache.
+func WithErrorTTL(ttl time.Duration) Option {
+	return func(c *config) {
+		c.errorTTL = ttl
+	}
+}
+
+// WithCodec задает формат снимка кэша. По умолчанию используется GobCodec.
+func WithCodec(codec Codec) Option {
+	return func(c *config) {
+		if codec != nil {
+			c.codec = codec
+		}
+	}
+}
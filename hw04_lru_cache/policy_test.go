@@ -0,0 +1,314 @@
+package hw04lrucache
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// политики вытеснения, проверяемые общим набором тестов.
+var policies = []struct {
+	name string
+	new  func(capacity int) Cache
+}{
+	{name: "LRU", new: NewCache},
+	{name: "LFU", new: NewLFUCache},
+	{name: "2Q", new: New2QCache},
+	{name: "ARC", new: NewARCCache},
+}
+
+func TestCachePolicies(t *testing.T) {
+	for _, p := range policies {
+		t.Run(p.name, func(t *testing.T) {
+			testCacheConformance(t, p.new)
+		})
+	}
+}
+
+func testCacheConformance(t *testing.T, newCache func(capacity int) Cache) {
+	t.Helper()
+
+	t.Run("empty cache", func(t *testing.T) {
+		c := newCache(10)
+
+		val, ok := c.Get("aaa")
+		require.False(t, ok)
+		require.Nil(t, val)
+	})
+
+	t.Run("simple", func(t *testing.T) {
+		c := newCache(5)
+
+		require.False(t, c.Set("aaa", 100))
+		require.False(t, c.Set("bbb", 200))
+
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 100, val)
+
+		require.True(t, c.Set("aaa", 300))
+		require.True(t, c.Set("bbb", 400))
+
+		val, ok = c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 300, val)
+
+		val, ok = c.Get("bbb")
+		require.True(t, ok)
+		require.Equal(t, 400, val)
+	})
+
+	t.Run("purge logic", func(t *testing.T) {
+		c := newCache(5)
+		c.Set("100", 100)
+		c.Set("200", 200)
+		c.Get("200")
+		c.Clear()
+
+		_, ok := c.Get("100")
+		require.False(t, ok)
+		_, ok = c.Get("200")
+		require.False(t, ok)
+
+		require.False(t, c.Set("100", 100))
+	})
+
+	t.Run("capacity", func(t *testing.T) {
+		const capacity = 8
+		c := newCache(capacity)
+
+		for i := 0; i < 100; i++ {
+			key := Key(strconv.Itoa(i % 20))
+			c.Set(key, i)
+			if i%3 == 0 {
+				c.Get(key)
+			}
+		}
+
+		val, ok := c.Get("19") // последний добавленный ключ не вытесняется
+		require.True(t, ok)
+		require.Equal(t, 99, val)
+
+		present := 0
+		for i := 0; i < 20; i++ {
+			if _, ok := c.Get(Key(strconv.Itoa(i))); ok {
+				present++
+			}
+		}
+		require.Equal(t, capacity, present) // кэш заполнен полностью
+	})
+
+	t.Run("multithreading", func(t *testing.T) {
+		c := newCache(10)
+		wg := &sync.WaitGroup{}
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10_000; i++ {
+				c.Set(Key(strconv.Itoa(i)), i)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10_000; i++ {
+				c.Get(Key(strconv.Itoa(rand.Intn(10_000))))
+			}
+		}()
+
+		wg.Wait()
+	})
+}
+
+func TestLFUCache(t *testing.T) {
+	c := NewLFUCache(3)
+	c.Set("100", 100)
+	c.Set("200", 200)
+	c.Set("300", 300)
+
+	c.Get("100")
+	c.Get("100")
+	c.Get("300")
+
+	c.Set("400", 400) // вытесняет 200 - к нему не было повторных обращений
+	_, ok := c.Get("200")
+	require.False(t, ok)
+
+	c.Get("400")
+	c.Set("500", 500) // 300 и 400 с равным числом обращений, вытесняется давний 300
+	_, ok = c.Get("300")
+	require.False(t, ok)
+
+	for _, k := range []Key{"100", "400", "500"} {
+		_, ok = c.Get(k)
+		require.True(t, ok, k)
+	}
+}
+
+func TestScanResistance(t *testing.T) {
+	const capacity = 20
+
+	hot := make([]Key, capacity/2)
+	for i := range hot {
+		hot[i] = Key("hot-" + strconv.Itoa(i))
+	}
+
+	for _, p := range policies {
+		t.Run(p.name, func(t *testing.T) {
+			c := p.new(capacity)
+			for i := 0; i < 3; i++ { // горячий набор используется многократно
+				for _, k := range hot {
+					if _, ok := c.Get(k); !ok {
+						c.Set(k, k)
+					}
+				}
+			}
+
+			for i := 0; i < capacity*5; i++ { // однократный проход по новым ключам
+				c.Set(Key("scan-"+strconv.Itoa(i)), i)
+			}
+
+			retained := 0
+			for _, k := range hot {
+				if _, ok := c.Get(k); ok {
+					retained++
+				}
+			}
+
+			if p.name == "LRU" {
+				require.Zero(t, retained, "LRU is not scan resistant")
+				return
+			}
+			require.Equal(t, len(hot), retained, "hot set was flushed by scan")
+		})
+	}
+}
+
+//go:generate go test -run TestTraces -update-traces
+
+var updateTraces = flag.Bool("update-traces", false, "перезаписать трассы в testdata")
+
+// синтетические трассы обращений к кэшу для BenchmarkCachePolicies.
+// Трассы строятся детерминированно по seed и перезаписываются командой go generate.
+var traces = []struct {
+	name string
+	seed int64
+	gen  func(r *rand.Rand) []Key
+}{
+	// 10000 ключей key-N, N распределено по Ципфу (s=1.1, v=1, N от 0 до 999)
+	{name: "zipf.trace", seed: 1, gen: zipfTrace},
+	// циклы из 400 обращений к горячему набору hot-N, N-1 распределено по Ципфу (s=1.1, v=1, N от 1 до 100),
+	// и сканирования 300 новых ключей scan-N; всего 10000 ключей
+	{name: "hotset_scan.trace", seed: 2, gen: hotsetScanTrace},
+}
+
+const traceLen = 10_000
+
+func zipfTrace(r *rand.Rand) []Key {
+	z := rand.NewZipf(r, 1.1, 1, 999)
+
+	keys := make([]Key, traceLen)
+	for i := range keys {
+		keys[i] = Key(fmt.Sprintf("key-%d", z.Uint64()))
+	}
+
+	return keys
+}
+
+func hotsetScanTrace(r *rand.Rand) []Key {
+	const (
+		hotRun  = 400
+		scanRun = 300
+	)
+	z := rand.NewZipf(r, 1.1, 1, 99)
+
+	keys := make([]Key, 0, traceLen)
+	scanned := 0
+	for len(keys) < traceLen {
+		for i := 0; i < hotRun && len(keys) < traceLen; i++ {
+			keys = append(keys, Key(fmt.Sprintf("hot-%d", z.Uint64()+1)))
+		}
+		for i := 0; i < scanRun && len(keys) < traceLen; i++ {
+			keys = append(keys, Key(fmt.Sprintf("scan-%d", scanned)))
+			scanned++
+		}
+	}
+
+	return keys
+}
+
+// трассы в testdata совпадают с результатом генерации; с флагом -update-traces перезаписываются.
+func TestTraces(t *testing.T) {
+	for _, tr := range traces {
+		t.Run(tr.name, func(t *testing.T) {
+			keys := tr.gen(rand.New(rand.NewSource(tr.seed)))
+
+			var sb strings.Builder
+			for _, k := range keys {
+				sb.WriteString(string(k) + "\n")
+			}
+
+			path := filepath.Join("testdata", tr.name)
+			if *updateTraces {
+				require.NoError(t, os.WriteFile(path, []byte(sb.String()), 0o644))
+			}
+
+			require.Equal(t, keys, loadTrace(t, tr.name))
+		})
+	}
+}
+
+// загрузить трассу обращений к кэшу: по одному ключу в строке.
+func loadTrace(tb testing.TB, name string) []Key {
+	tb.Helper()
+
+	f, err := os.Open(filepath.Join("testdata", name))
+	require.NoError(tb, err)
+	defer f.Close()
+
+	keys := make([]Key, 0, traceLen)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		keys = append(keys, Key(scanner.Text()))
+	}
+	require.NoError(tb, scanner.Err())
+
+	return keys
+}
+
+func BenchmarkCachePolicies(b *testing.B) {
+	for _, tr := range traces {
+		trace := tr.name
+		keys := loadTrace(b, trace)
+
+		for _, p := range policies {
+			b.Run(trace+"/"+p.name, func(b *testing.B) {
+				var hits, total int
+
+				for i := 0; i < b.N; i++ {
+					c := p.new(100)
+					for _, k := range keys {
+						if _, ok := c.Get(k); ok {
+							hits++
+						} else {
+							c.Set(k, k)
+						}
+					}
+					total += len(keys)
+				}
+
+				b.ReportMetric(float64(hits)/float64(total), "hit-ratio")
+			})
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package hw04lrucache
+
+// очередь элементов кэша с индексом по ключу.
+// Используется политиками вытеснения, которым нужно несколько очередей.
+type keyedQueue[K comparable, V any] struct {
+	list  TypedList[*cacheItem[K, V]]
+	items map[K]*TypedListItem[*cacheItem[K, V]]
+}
+
+func newKeyedQueue[K comparable, V any](capacity int) *keyedQueue[K, V] {
+	return &keyedQueue[K, V]{
+		list:  NewTypedList[*cacheItem[K, V]](),
+		items: make(map[K]*TypedListItem[*cacheItem[K, V]], capacity),
+	}
+}
+
+func (q *keyedQueue[K, V]) Len() int {
+	return q.list.Len()
+}
+
+// найти элемент по ключу.
+func (q *keyedQueue[K, V]) get(key K) (*TypedListItem[*cacheItem[K, V]], bool) {
+	itm, ok := q.items[key]
+	return itm, ok
+}
+
+// добавить элемент в начало очереди.
+func (q *keyedQueue[K, V]) pushFront(key K, value V) {
+	q.items[key] = q.list.PushFront(&cacheItem[K, V]{Key: key, Value: value})
+}
+
+// удалить элемент из очереди.
+func (q *keyedQueue[K, V]) remove(itm *TypedListItem[*cacheItem[K, V]]) {
+	delete(q.items, itm.Value.Key)
+	q.list.Remove(itm)
+}
+
+// удалить и вернуть самый старый элемент очереди.
+func (q *keyedQueue[K, V]) removeOldest() *cacheItem[K, V] {
+	itm := q.list.Back()
+	if itm == nil {
+		return nil
+	}
+
+	q.remove(itm)
+	return itm.Value
+}
+
+// удалить все элементы очереди.
+func (q *keyedQueue[K, V]) clear() {
+	q.list = NewTypedList[*cacheItem[K, V]]()
+	q.items = make(map[K]*TypedListItem[*cacheItem[K, V]], len(q.items))
+}
@@ -0,0 +1,76 @@
+package hw04lrucache
+
+// шардированный кэш: ключи распределяются по независимым LRU-кэшам,
+// каждый со своей блокировкой.
+type shardedCache[K comparable, V any] struct {
+	shards []*lruCache[K, V]
+	hash   func(key K) uint64
+}
+
+// Создать новый шардированный LRU-кэш.
+// Общая емкость capacity делится между shards шардами: остаток от деления распределяется
+// по одному элементу на первые шарды. Шардов не больше, чем capacity.
+func NewShardedCache(capacity, shards int) Cache {
+	return newShardedCache[Key, interface{}](capacity, shards, hashKey)
+}
+
+func newShardedCache[K comparable, V any](capacity, shards int, hash func(key K) uint64) *shardedCache[K, V] {
+	if shards < 1 {
+		shards = 1
+	}
+	if capacity > 0 && shards > capacity { // шард с нулевой емкостью не ограничен
+		shards = capacity
+	}
+
+	c := &shardedCache[K, V]{
+		shards: make([]*lruCache[K, V], shards),
+		hash:   hash,
+	}
+	for i := range c.shards {
+		shardCapacity := capacity / shards
+		if i < capacity%shards {
+			shardCapacity++
+		}
+		c.shards[i] = newLRUCache[K, V](shardCapacity)
+	}
+
+	return c
+}
+
+// Добавить значение в кэш.
+func (c *shardedCache[K, V]) Set(key K, value V) bool {
+	return c.shard(key).Set(key, value)
+}
+
+// Получить значение из кэша.
+func (c *shardedCache[K, V]) Get(key K) (V, bool) {
+	return c.shard(key).Get(key)
+}
+
+// Очистить кэш.
+func (c *shardedCache[K, V]) Clear() {
+	for _, s := range c.shards {
+		s.Clear()
+	}
+}
+
+// шард, в котором хранится ключ.
+func (c *shardedCache[K, V]) shard(key K) *lruCache[K, V] {
+	return c.shards[c.hash(key)%uint64(len(c.shards))]
+}
+
+// хэш FNV-1a строкового ключа.
+func hashKey(key Key) uint64 {
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+
+	h := uint64(offset64)
+	for i := 0; i < len(key); i++ {
+		h ^= uint64(key[i])
+		h *= prime64
+	}
+
+	return h
+}
@@ -0,0 +1,148 @@
+package hw04lrucache
+
+import (
+	"math/rand"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardedCache(t *testing.T) {
+	t.Run("simple", func(t *testing.T) {
+		c := NewShardedCache(16, 4)
+
+		wasInCache := c.Set("aaa", 100)
+		require.False(t, wasInCache)
+
+		wasInCache = c.Set("aaa", 200)
+		require.True(t, wasInCache)
+
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 200, val)
+
+		val, ok = c.Get("bbb")
+		require.False(t, ok)
+		require.Nil(t, val)
+	})
+
+	t.Run("purge logic", func(t *testing.T) {
+		c := NewShardedCache(16, 4)
+		for i := 0; i < 10; i++ {
+			c.Set(Key(strconv.Itoa(i)), i)
+		}
+		c.Clear()
+
+		for i := 0; i < 10; i++ {
+			_, ok := c.Get(Key(strconv.Itoa(i)))
+			require.False(t, ok)
+		}
+	})
+
+	t.Run("capacity", func(t *testing.T) {
+		c := newShardedCache[Key, interface{}](10, 4, hashKey)
+		for i := 0; i < 1000; i++ {
+			c.Set(Key(strconv.Itoa(i)), i)
+		}
+
+		total := 0
+		for i, s := range c.shards {
+			require.Equal(t, []int{3, 3, 2, 2}[i], s.queue.Len())
+			total += s.queue.Len()
+		}
+		require.Equal(t, 10, total)
+	})
+
+	t.Run("more shards than capacity", func(t *testing.T) {
+		c := newShardedCache[Key, interface{}](3, 8, hashKey)
+		require.Len(t, c.shards, 3)
+
+		for i := 0; i < 1000; i++ {
+			c.Set(Key(strconv.Itoa(i)), i)
+		}
+		for _, s := range c.shards {
+			require.Equal(t, 1, s.queue.Len())
+		}
+	})
+
+	t.Run("invalid shards", func(t *testing.T) {
+		c := newShardedCache[Key, interface{}](10, 0, hashKey)
+		require.Len(t, c.shards, 1)
+	})
+}
+
+func TestShardedCacheMultithreading(_ *testing.T) {
+	c := NewShardedCache(10, 4)
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1_000_000; i++ {
+			c.Set(Key(strconv.Itoa(i)), i)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1_000_000; i++ {
+			c.Get(Key(strconv.Itoa(rand.Intn(1_000_000))))
+		}
+	}()
+
+	wg.Wait()
+}
+
+func BenchmarkCache(b *testing.B) {
+	const (
+		capacity = 1024
+		keyCount = 4096
+	)
+
+	keys := make([]Key, keyCount)
+	for i := range keys {
+		keys[i] = Key(strconv.Itoa(i))
+	}
+
+	caches := []struct {
+		name string
+		new  func() Cache
+	}{
+		{name: "single", new: func() Cache { return NewCache(capacity) }},
+		{name: "sharded", new: func() Cache { return NewShardedCache(capacity, 16) }},
+	}
+
+	mixes := []struct {
+		name      string
+		writePerc int // доля записей в процентах
+	}{
+		{name: "read-heavy", writePerc: 10},
+		{name: "write-heavy", writePerc: 90},
+	}
+
+	for _, cc := range caches {
+		for _, mix := range mixes {
+			b.Run(cc.name+"/"+mix.name, func(b *testing.B) {
+				c := cc.new()
+				for _, k := range keys[:capacity] {
+					c.Set(k, k)
+				}
+
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					r := rand.New(rand.NewSource(rand.Int63()))
+					for pb.Next() {
+						k := keys[r.Intn(keyCount)]
+						if r.Intn(100) < mix.writePerc {
+							c.Set(k, k)
+						} else {
+							c.Get(k)
+						}
+					}
+				})
+			})
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package hw04lrucache
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// версия формата снимка кэша.
+const snapshotVersion = 1
+
+// ErrSnapshotVersion - неподдерживаемая версия снимка кэша.
+var ErrSnapshotVersion = errors.New("unsupported snapshot version")
+
+// Encoder - кодировщик записей снимка кэша.
+type Encoder interface {
+	Encode(v interface{}) error
+}
+
+// Decoder - декодировщик записей снимка кэша.
+type Decoder interface {
+	Decode(v interface{}) error
+}
+
+// Codec - формат снимка кэша.
+type Codec interface {
+	NewEncoder(w io.Writer) Encoder
+	NewDecoder(r io.Reader) Decoder
+}
+
+// GobCodec - снимок кэша в формате gob.
+// Конкретные типы значений, хранимых как interface{}, должны быть зарегистрированы через gob.Register.
+type GobCodec struct{}
+
+func (GobCodec) NewEncoder(w io.Writer) Encoder { return gob.NewEncoder(w) }
+func (GobCodec) NewDecoder(r io.Reader) Decoder { return gob.NewDecoder(r) }
+
+// JSONCodec - снимок кэша в формате JSON.
+// Значения, хранимые как interface{}, восстанавливаются в типы по умолчанию пакета encoding/json.
+type JSONCodec struct{}
+
+func (JSONCodec) NewEncoder(w io.Writer) Encoder { return json.NewEncoder(w) }
+func (JSONCodec) NewDecoder(r io.Reader) Decoder { return json.NewDecoder(r) }
+
+// заголовок снимка кэша.
+type snapshotHeader struct {
+	Version int
+	Count   int // количество записей
+}
+
+// запись снимка кэша.
+type snapshotEntry[K comparable, V any] struct {
+	Key   K
+	Value V
+	TTL   time.Duration // оставшееся время жизни, 0 - бессрочно
+	Cost  int64
+}
+
+// Сохранить содержимое кэша в w.
+// Записи сохраняются от давно использованных к недавно использованным, устаревшие записи пропускаются.
+func (c *lruCache[K, V]) Snapshot(w io.Writer) error {
+	c.mtx.Lock()
+	now := c.clock.Now()
+	entries := make([]snapshotEntry[K, V], 0, c.queue.Len())
+	for ci := range c.queue.Backward() {
+		if ci.expired(now) {
+			continue
+		}
+
+		entry := snapshotEntry[K, V]{Key: ci.Key, Value: ci.Value, Cost: ci.cost}
+		if !ci.expiresAt.IsZero() {
+			entry.TTL = ci.expiresAt.Sub(now)
+		}
+		entries = append(entries, entry)
+	}
+	c.mtx.Unlock()
+
+	enc := c.codec.NewEncoder(w)
+	if err := enc.Encode(snapshotHeader{Version: snapshotVersion, Count: len(entries)}); err != nil {
+		return fmt.Errorf("encode snapshot header: %w", err)
+	}
+	for i := range entries {
+		if err := enc.Encode(&entries[i]); err != nil {
+			return fmt.Errorf("encode snapshot entry %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// Загрузить в кэш содержимое, сохраненное методом Snapshot.
+// Порядок использования записей сохраняется, записи с ограниченным временем жизни
+// получают оставшееся на момент сохранения время.
+func (c *lruCache[K, V]) Restore(r io.Reader) error {
+	dec := c.codec.NewDecoder(r)
+
+	var header snapshotHeader
+	if err := dec.Decode(&header); err != nil {
+		return fmt.Errorf("decode snapshot header: %w", err)
+	}
+	if header.Version != snapshotVersion {
+		return fmt.Errorf("%w: %d", ErrSnapshotVersion, header.Version)
+	}
+
+	for i := 0; i < header.Count; i++ {
+		var entry snapshotEntry[K, V]
+		if err := dec.Decode(&entry); err != nil {
+			return fmt.Errorf("decode snapshot entry %d: %w", i, err)
+		}
+
+		var expiresAt time.Time
+		if entry.TTL > 0 {
+			expiresAt = c.clock.Now().Add(entry.TTL)
+		}
+		// запись, превышающая бюджет кэша, пропускается
+		if _, err := c.set(entry.Key, entry.Value, expiresAt, entry.Cost); err != nil &&
+			!errors.Is(err, ErrCostExceedsBudget) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package hw04lrucache
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// ключи кэша от недавно использованных к давно использованным.
+func cacheKeys[K comparable, V any](c *lruCache[K, V]) []K {
+	keys := make([]K, 0, c.queue.Len())
+	for ci := range c.queue.All() {
+		keys = append(keys, ci.Key)
+	}
+	return keys
+}
+
+func TestCacheSnapshot(t *testing.T) {
+	codecs := []struct {
+		name  string
+		codec Codec
+	}{
+		{name: "gob", codec: GobCodec{}},
+		{name: "json", codec: JSONCodec{}},
+	}
+
+	for _, cc := range codecs {
+		t.Run(cc.name, func(t *testing.T) {
+			src := newLRUCache[Key, int](5, WithCodec(cc.codec))
+			src.Set("100", 100)
+			src.Set("200", 200)
+			src.Set("300", 300)
+			src.Get("100") // 100, 300, 200
+
+			buf := &bytes.Buffer{}
+			require.NoError(t, src.Snapshot(buf))
+
+			dst := newLRUCache[Key, int](5, WithCodec(cc.codec))
+			require.NoError(t, dst.Restore(buf))
+
+			require.Equal(t, []Key{"100", "300", "200"}, cacheKeys(dst))
+
+			val, ok := dst.Get("300")
+			require.True(t, ok)
+			require.Equal(t, 300, val)
+		})
+	}
+
+	t.Run("interface values", func(t *testing.T) {
+		src := NewCache(5).(LRUCache[Key, interface{}])
+		src.Set("aaa", 100)
+		src.Set("bbb", "value")
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, src.Snapshot(buf))
+
+		dst := NewCache(5).(LRUCache[Key, interface{}])
+		require.NoError(t, dst.Restore(buf))
+
+		val, ok := dst.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 100, val)
+
+		val, ok = dst.Get("bbb")
+		require.True(t, ok)
+		require.Equal(t, "value", val)
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		clock := newFakeClock()
+		src := newLRUCache[Key, int](5, WithClock(clock))
+		src.SetWithTTL("100", 100, time.Second)
+		src.SetWithTTL("200", 200, time.Minute)
+		src.Set("300", 300)
+
+		clock.Advance(time.Second) // 100 устарел
+		buf := &bytes.Buffer{}
+		require.NoError(t, src.Snapshot(buf))
+
+		clock.Advance(time.Hour) // время простоя не учитывается
+		dst := newLRUCache[Key, int](5, WithClock(clock))
+		require.NoError(t, dst.Restore(buf))
+		require.Equal(t, []Key{"300", "200"}, cacheKeys(dst))
+
+		clock.Advance(58 * time.Second) // из оставшихся 59 секунд
+		_, ok := dst.Get("200")
+		require.True(t, ok)
+
+		clock.Advance(time.Second)
+		_, ok = dst.Get("200")
+		require.False(t, ok)
+
+		_, ok = dst.Get("300")
+		require.True(t, ok)
+	})
+
+	t.Run("smaller capacity", func(t *testing.T) {
+		src := newLRUCache[Key, int](5)
+		for _, k := range []Key{"100", "200", "300", "400"} {
+			src.SetWithCost(k, 1, 2)
+		}
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, src.Snapshot(buf))
+
+		dst := newLRUCache[Key, int](0, WithCostBudget(5))
+		require.NoError(t, dst.Restore(buf))
+		require.Equal(t, []Key{"400", "300"}, cacheKeys(dst))
+	})
+
+	t.Run("invalid data", func(t *testing.T) {
+		c := newLRUCache[Key, int](5)
+		require.Error(t, c.Restore(bytes.NewBufferString("invalid")))
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, GobCodec{}.NewEncoder(buf).Encode(snapshotHeader{Version: 100}))
+		err := c.Restore(buf)
+		require.True(t, errors.Is(err, ErrSnapshotVersion), "actual err - %v", err)
+	})
+}
@@ -0,0 +1,63 @@
+package hw04lrucache
+
+import "sync/atomic"
+
+// Stats - статистика использования кэша.
+type Stats struct {
+	Hits        uint64 // количество успешных чтений
+	Misses      uint64 // количество чтений отсутствующих ключей
+	Sets        uint64 // количество добавлений новых ключей
+	Updates     uint64 // количество обновлений существующих ключей
+	Evictions   uint64 // количество вытеснений при заполнении кэша
+	Expirations uint64 // количество удалений по истечении времени жизни
+	Len         int    // текущее количество элементов в кэше
+	Cost        int64  // текущая суммарная стоимость элементов
+}
+
+// HitRatio - доля успешных чтений.
+func (s Stats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(total)
+}
+
+// счетчики статистики кэша, изменяются без блокировки кэша.
+type stats struct {
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	sets        atomic.Uint64
+	updates     atomic.Uint64
+	evictions   atomic.Uint64
+	expirations atomic.Uint64
+}
+
+// Получить статистику использования кэша.
+func (c *lruCache[K, V]) Stats() Stats {
+	c.mtx.Lock()
+	length, cost := c.queue.Len(), c.cost
+	c.mtx.Unlock()
+
+	return Stats{
+		Hits:        c.stats.hits.Load(),
+		Misses:      c.stats.misses.Load(),
+		Sets:        c.stats.sets.Load(),
+		Updates:     c.stats.updates.Load(),
+		Evictions:   c.stats.evictions.Load(),
+		Expirations: c.stats.expirations.Load(),
+		Len:         length,
+		Cost:        cost,
+	}
+}
+
+// Сбросить статистику использования кэша.
+func (c *lruCache[K, V]) ResetStats() {
+	c.stats.hits.Store(0)
+	c.stats.misses.Store(0)
+	c.stats.sets.Store(0)
+	c.stats.updates.Store(0)
+	c.stats.evictions.Store(0)
+	c.stats.expirations.Store(0)
+}
@@ -0,0 +1,76 @@
+package hw04lrucache
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheStats(t *testing.T) {
+	t.Run("counters", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewLRUCache[Key, int](2, WithClock(clock))
+
+		c.Set("100", 100)
+		c.Set("200", 200)
+		c.Set("100", 101) // обновление
+		c.Set("300", 300) // вытесняет 200
+
+		c.Get("100")
+		c.Get("300")
+		c.Get("200")
+
+		c.SetWithTTL("400", 400, time.Second) // вытесняет 100
+		clock.Advance(time.Second)
+		c.Get("400")
+
+		require.Equal(t, Stats{
+			Hits:        2,
+			Misses:      2,
+			Sets:        4,
+			Updates:     1,
+			Evictions:   2,
+			Expirations: 1,
+			Len:         1,
+			Cost:        1,
+		}, c.Stats())
+		require.InDelta(t, 0.5, c.Stats().HitRatio(), 1e-9)
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		c := NewLRUCache[Key, int](2)
+		c.Set("100", 100)
+		c.Get("100")
+		c.Get("200")
+
+		c.ResetStats()
+
+		require.Equal(t, Stats{Len: 1, Cost: 1}, c.Stats())
+		require.Zero(t, c.Stats().HitRatio())
+	})
+
+	t.Run("multithreading", func(t *testing.T) {
+		c := NewLRUCache[Key, int](10)
+		wg := sync.WaitGroup{}
+
+		for g := 0; g < 4; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 1000; i++ {
+					c.Set(Key(strconv.Itoa(i)), i)
+					c.Get(Key(strconv.Itoa(i)))
+				}
+			}()
+		}
+		wg.Wait()
+
+		st := c.Stats()
+		require.Equal(t, uint64(4000), st.Sets+st.Updates)
+		require.Equal(t, uint64(4000), st.Hits+st.Misses)
+		require.Equal(t, 10, st.Len)
+	})
+}
@@ -0,0 +1,53 @@
+package hw04lrucache
+
+import "time"
+
+// элемент устарел к моменту now.
+func (i *cacheItem[K, V]) expired(now time.Time) bool {
+	return !i.expiresAt.IsZero() && !now.Before(i.expiresAt)
+}
+
+// Удалить из кэша все устаревшие элементы.
+func (c *lruCache[K, V]) DeleteExpired() {
+	c.mtx.Lock()
+	defer c.unlockAndNotify()
+
+	now := c.clock.Now()
+	c.queue.RemoveIf(func(ci *cacheItem[K, V]) bool {
+		if !ci.expired(now) {
+			return false
+		}
+
+		c.forget(ci, EvictExpired)
+		return true
+	})
+}
+
+// Остановить фоновую очистку кэша.
+func (c *lruCache[K, V]) Close() {
+	if c.stop == nil {
+		return
+	}
+
+	c.closeOnce.Do(func() {
+		close(c.stop)
+		<-c.stopped
+	})
+}
+
+// фоновая очистка устаревших элементов.
+func (c *lruCache[K, V]) janitor(interval time.Duration) {
+	defer close(c.stopped)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.DeleteExpired()
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package hw04lrucache
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// управляемый источник времени для тестов.
+type fakeClock struct {
+	mtx sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mtx.Lock()
+	c.now = c.now.Add(d)
+	c.mtx.Unlock()
+}
+
+func TestCacheTTL(t *testing.T) {
+	t.Run("expired is miss", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewLRUCache[Key, int](5, WithClock(clock))
+
+		c.SetWithTTL("aaa", 100, time.Minute)
+		c.Set("bbb", 200)
+
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 100, val)
+
+		clock.Advance(time.Minute)
+
+		val, ok = c.Get("aaa")
+		require.False(t, ok)
+		require.Zero(t, val)
+
+		val, ok = c.Get("bbb") // бессрочный элемент
+		require.True(t, ok)
+		require.Equal(t, 200, val)
+	})
+
+	t.Run("set over expired", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewLRUCache[Key, int](5, WithClock(clock))
+
+		c.SetWithTTL("aaa", 100, time.Second)
+		clock.Advance(2 * time.Second)
+
+		wasInCache := c.SetWithTTL("aaa", 300, time.Second)
+		require.False(t, wasInCache)
+
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 300, val)
+	})
+
+	t.Run("set resets ttl", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewLRUCache[Key, int](5, WithClock(clock))
+
+		c.SetWithTTL("aaa", 100, time.Second)
+		wasInCache := c.Set("aaa", 200)
+		require.True(t, wasInCache)
+
+		clock.Advance(time.Hour)
+
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 200, val)
+	})
+
+	t.Run("delete expired", func(t *testing.T) {
+		clock := newFakeClock()
+		c := newLRUCache[Key, int](5, WithClock(clock))
+
+		c.SetWithTTL("100", 100, time.Second)
+		c.SetWithTTL("200", 200, time.Minute)
+		c.SetWithTTL("300", 300, time.Second)
+		c.Set("400", 400)
+
+		clock.Advance(time.Second)
+		c.DeleteExpired()
+
+		require.Equal(t, 2, c.queue.Len())
+		require.Len(t, c.items, 2)
+
+		elems := make([]Key, 0, 2)
+		for i := c.queue.Front(); i != nil; i = i.Next {
+			elems = append(elems, i.Value.Key)
+		}
+		require.Equal(t, []Key{"400", "200"}, elems)
+	})
+
+	t.Run("janitor", func(t *testing.T) {
+		clock := newFakeClock()
+		c := newLRUCache[Key, int](5, WithClock(clock), WithJanitor(time.Millisecond))
+		defer c.Close()
+
+		c.SetWithTTL("aaa", 100, time.Second)
+		c.Set("bbb", 200)
+		clock.Advance(time.Second)
+
+		require.Eventually(t, func() bool {
+			c.mtx.Lock()
+			defer c.mtx.Unlock()
+
+			_, ok := c.items["aaa"]
+			return !ok
+		}, time.Second, time.Millisecond)
+
+		val, ok := c.Get("bbb")
+		require.True(t, ok)
+		require.Equal(t, 200, val)
+	})
+
+	t.Run("close", func(t *testing.T) {
+		c := NewLRUCache[Key, int](5, WithJanitor(time.Millisecond))
+		c.Close()
+		c.Close() // повторный вызов безопасен
+
+		NewLRUCache[Key, int](5).Close() // без фоновой очистки
+	})
+}
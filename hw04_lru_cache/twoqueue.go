@@ -0,0 +1,128 @@
+package hw04lrucache
+
+import "sync"
+
+const (
+	twoQueueRecentRatio = 0.25 // доля емкости под очередь новых элементов
+	twoQueueGhostRatio  = 0.5  // доля емкости под очередь ключей вытесненных новых элементов
+)
+
+// 2Q-кэш: новые элементы попадают в очередь recent и переходят в очередь frequent
+// только при повторном обращении, поэтому однократный проход по ключам
+// не вытесняет часто используемые элементы.
+type twoQueueCache[K comparable, V any] struct {
+	capacity   int
+	recentSize int // емкость очереди recent
+	mtx        sync.Mutex
+	recent     *keyedQueue[K, V]        // элементы, к которым было одно обращение
+	frequent   *keyedQueue[K, V]        // элементы, к которым было несколько обращений
+	ghost      *keyedQueue[K, struct{}] // ключи элементов, вытесненных из recent
+	ghostSize  int                      // емкость очереди ghost
+}
+
+// Создать новый 2Q-кэш.
+func New2QCache(capacity int) Cache {
+	return NewTyped2QCache[Key, interface{}](capacity)
+}
+
+// Создать новый 2Q-кэш с ключами типа K и значениями типа V.
+func NewTyped2QCache[K comparable, V any](capacity int) TypedCache[K, V] {
+	c := &twoQueueCache[K, V]{
+		capacity:   capacity,
+		recentSize: int(float64(capacity) * twoQueueRecentRatio),
+		ghostSize:  int(float64(capacity) * twoQueueGhostRatio),
+	}
+	c.init()
+
+	return c
+}
+
+func (c *twoQueueCache[K, V]) init() {
+	c.recent = newKeyedQueue[K, V](c.recentSize)
+	c.frequent = newKeyedQueue[K, V](c.capacity)
+	c.ghost = newKeyedQueue[K, struct{}](c.ghostSize)
+}
+
+// Добавить значение в кэш.
+func (c *twoQueueCache[K, V]) Set(key K, value V) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if itm, ok := c.frequent.get(key); ok {
+		itm.Value.Value = value
+		c.frequent.list.MoveToFront(itm)
+		return true
+	}
+
+	if itm, ok := c.recent.get(key); ok { // повторное обращение
+		c.recent.remove(itm)
+		c.frequent.pushFront(key, value)
+		return true
+	}
+
+	if itm, ok := c.ghost.get(key); ok { // элемент недавно вытеснен из recent
+		c.ghost.remove(itm)
+		c.ensureSpace(true)
+		c.frequent.pushFront(key, value)
+		return false
+	}
+
+	c.ensureSpace(false)
+	c.recent.pushFront(key, value)
+
+	return false
+}
+
+// Получить значение из кэша.
+func (c *twoQueueCache[K, V]) Get(key K) (V, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if itm, ok := c.frequent.get(key); ok {
+		c.frequent.list.MoveToFront(itm)
+		return itm.Value.Value, true
+	}
+
+	if itm, ok := c.recent.get(key); ok { // повторное обращение
+		c.recent.remove(itm)
+		c.frequent.pushFront(key, itm.Value.Value)
+		return itm.Value.Value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Очистить кэш.
+func (c *twoQueueCache[K, V]) Clear() {
+	c.mtx.Lock()
+	c.init()
+	c.mtx.Unlock()
+}
+
+// освободить место для нового элемента.
+// fromGhost - новый элемент найден в очереди ghost.
+func (c *twoQueueCache[K, V]) ensureSpace(fromGhost bool) {
+	recentLen := c.recent.Len()
+	if c.capacity <= 0 || recentLen+c.frequent.Len() < c.capacity {
+		return
+	}
+
+	if recentLen > 0 && (recentLen > c.recentSize || (recentLen == c.recentSize && !fromGhost)) {
+		excess := c.recent.removeOldest()
+		if c.ghost.Len() >= c.ghostSize {
+			c.ghost.removeOldest()
+		}
+		if c.ghostSize > 0 {
+			c.ghost.pushFront(excess.Key, struct{}{})
+		}
+		return
+	}
+
+	if c.frequent.Len() > 0 {
+		c.frequent.removeOldest()
+		return
+	}
+
+	c.recent.removeOldest()
+}
@@ -0,0 +1,102 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TaskError - ошибка выполнения задачи.
+type TaskError struct {
+	Index int   // номер задачи
+	Err   error // ошибка, которую вернула задача
+}
+
+func (e TaskError) Error() string {
+	return fmt.Sprintf("task %d: %v", e.Index, e.Err)
+}
+
+func (e TaskError) Unwrap() error {
+	return e.Err
+}
+
+// PanicError - паника при выполнении задачи.
+type PanicError struct {
+	Value interface{} // значение, переданное в panic
+	Stack []byte      // стек вызовов в момент паники
+}
+
+func (e PanicError) Error() string {
+	return fmt.Sprintf("task panicked: %v", e.Value)
+}
+
+// TimeoutError - задача не завершилась за отведенное время.
+// Соответствует context.DeadlineExceeded при проверке через errors.Is.
+type TimeoutError struct {
+	Timeout time.Duration // отведенное время
+}
+
+func (e TimeoutError) Error() string {
+	return fmt.Sprintf("task timed out after %v", e.Timeout)
+}
+
+func (e TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// RunError - ошибки выполнения задач.
+// Соответствует ErrErrorsLimitExceeded, если выполнение прекращено из-за превышения лимита ошибок,
+// и каждой из ошибок задач при проверке через errors.Is и errors.As.
+type RunError struct {
+	Errors        []TaskError // ошибки задач в порядке их номеров
+	LimitExceeded bool        // выполнение прекращено из-за превышения лимита ошибок
+	// Skipped - номера задач графа, которые не запускались, по возрастанию. Заполняется только RunGraph;
+	// задачи, пропущенные другими функциями после превышения лимита ошибок, сообщаются Observer.TaskSkipped.
+	Skipped []int
+}
+
+func (e RunError) Error() string {
+	s := strings.Builder{}
+	if e.LimitExceeded {
+		s.WriteString(ErrErrorsLimitExceeded.Error())
+	} else {
+		s.WriteString(fmt.Sprintf("%d tasks failed", len(e.Errors)))
+	}
+
+	for i, te := range e.Errors {
+		if i == 0 {
+			s.WriteString(": ")
+		} else {
+			s.WriteString("; ")
+		}
+		s.WriteString(te.Error())
+	}
+
+	if len(e.Skipped) > 0 {
+		s.WriteString(fmt.Sprintf(" (%d tasks skipped)", len(e.Skipped)))
+	}
+
+	return s.String()
+}
+
+func (e RunError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors)+1)
+	if e.LimitExceeded {
+		errs = append(errs, ErrErrorsLimitExceeded)
+	}
+	for _, te := range e.Errors {
+		errs = append(errs, te)
+	}
+
+	return errs
+}
+
+func newRunError(errs []TaskError, limitExceeded bool) RunError {
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Index < errs[j].Index
+	})
+
+	return RunError{Errors: errs, LimitExceeded: limitExceeded}
+}
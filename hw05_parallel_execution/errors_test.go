@@ -0,0 +1,109 @@
+package hw05parallelexecution
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errTest = errors.New("test error")
+
+type customError struct {
+	Code int
+}
+
+func (e customError) Error() string {
+	return "custom error"
+}
+
+func TestTaskError(t *testing.T) {
+	err := error(TaskError{Index: 3, Err: errTest})
+
+	require.Equal(t, "task 3: test error", err.Error())
+	require.True(t, errors.Is(err, errTest))
+}
+
+func TestRunError(t *testing.T) {
+	tests := []struct {
+		name string
+		data RunError
+		exp  string
+	}{
+		{
+			name: "limit_exceeded",
+			data: RunError{
+				Errors: []TaskError{
+					{Index: 0, Err: errTest},
+					{Index: 5, Err: io.EOF},
+				},
+				LimitExceeded: true,
+			},
+			exp: "errors limit exceeded: task 0: test error; task 5: EOF",
+		},
+		{
+			name: "collected",
+			data: RunError{
+				Errors: []TaskError{
+					{Index: 2, Err: errTest},
+				},
+			},
+			exp: "1 tasks failed: task 2: test error",
+		},
+		{
+			name: "empty_limit_exceeded",
+			data: RunError{LimitExceeded: true},
+			exp:  "errors limit exceeded",
+		},
+		{
+			name: "skipped",
+			data: RunError{
+				Errors:  []TaskError{{Index: 1, Err: errTest}},
+				Skipped: []int{2, 3},
+			},
+			exp: "1 tasks failed: task 1: test error (2 tasks skipped)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.exp, test.data.Error())
+		})
+	}
+
+	t.Run("is", func(t *testing.T) {
+		err := error(RunError{
+			Errors:        []TaskError{{Index: 1, Err: errTest}},
+			LimitExceeded: true,
+		})
+
+		require.True(t, errors.Is(err, ErrErrorsLimitExceeded))
+		require.True(t, errors.Is(err, errTest))
+		require.False(t, errors.Is(err, io.EOF))
+
+		err = RunError{Errors: []TaskError{{Index: 1, Err: errTest}}}
+		require.False(t, errors.Is(err, ErrErrorsLimitExceeded))
+	})
+
+	t.Run("as", func(t *testing.T) {
+		err := error(RunError{
+			Errors: []TaskError{
+				{Index: 1, Err: errTest},
+				{Index: 4, Err: customError{Code: 42}},
+			},
+		})
+
+		var te TaskError
+		require.True(t, errors.As(err, &te))
+		require.Equal(t, 1, te.Index)
+
+		var ce customError
+		require.True(t, errors.As(err, &ce))
+		require.Equal(t, 42, ce.Code)
+
+		var re RunError
+		require.True(t, errors.As(err, &re))
+		require.Len(t, re.Errors, 2)
+	})
+}
@@ -0,0 +1,228 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrUnknownDependency = errors.New("unknown dependency")
+	ErrDependencyCycle   = errors.New("dependency cycle")
+)
+
+// GraphTask - задача графа, запускаемая после успешного завершения задач, от которых она зависит.
+type GraphTask struct {
+	Task TaskCtx
+	Deps []int // номера задач, от которых зависит задача
+}
+
+// RunGraph выполняет задачи графа в opts.Workers горутинах с учетом зависимостей.
+// Граф проверяется до запуска задач: при неизвестной зависимости возвращается ErrUnknownDependency,
+// при наличии цикла - ErrDependencyCycle.
+// Задачи, зависящие от завершившихся ошибкой, и задачи, оставшиеся после превышения лимита ошибок,
+// не запускаются и возвращаются в RunError.Skipped.
+// При отмене ctx возвращается ошибка ctx.
+func RunGraph(ctx context.Context, tasks []GraphTask, opts Options) error {
+	if len(tasks) == 0 {
+		return ErrEmptyTasks
+	}
+
+	if err := validateGraph(tasks); err != nil {
+		return err
+	}
+
+	if len(tasks) < opts.Workers {
+		opts.Workers = len(tasks)
+	}
+
+	s := newGraphScheduler(tasks)
+	err := run(ctx, source{produce: s.produce, complete: s.complete, total: len(tasks)}, opts)
+
+	skipped := s.skipped()
+	var re RunError
+	switch {
+	case len(skipped) == 0 || ctx.Err() != nil:
+		return err
+	case errors.As(err, &re):
+		re.Skipped = skipped
+		return re
+	case err != nil:
+		return err
+	default: // ошибок меньше лимита, но зависящие от них задачи пропущены
+		return RunError{Errors: s.taskErrors(), Skipped: skipped}
+	}
+}
+
+// проверить, что зависимости существуют и не образуют цикл.
+func validateGraph(tasks []GraphTask) error {
+	for i, t := range tasks {
+		for _, d := range t.Deps {
+			if d < 0 || d >= len(tasks) {
+				return fmt.Errorf("%w: task %d depends on %d", ErrUnknownDependency, i, d)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		inPath
+		visited
+	)
+	state := make([]int, len(tasks))
+	var path []int
+
+	// обход в глубину; возвращает цикл, если он найден
+	var visit func(i int) []int
+	visit = func(i int) []int {
+		state[i] = inPath
+		path = append(path, i)
+		for _, d := range tasks[i].Deps {
+			switch state[d] {
+			case inPath:
+				for j := len(path) - 1; ; j-- {
+					if path[j] == d {
+						return append(path[j:], d)
+					}
+				}
+			case unvisited:
+				if cycle := visit(d); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = visited
+
+		return nil
+	}
+
+	for i := range tasks {
+		if state[i] != unvisited {
+			continue
+		}
+		if cycle := visit(i); cycle != nil {
+			ids := make([]string, len(cycle))
+			for j, c := range cycle {
+				ids[j] = strconv.Itoa(c)
+			}
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(ids, " -> "))
+		}
+	}
+
+	return nil
+}
+
+// планировщик задач графа: передает в работу задачи, все зависимости которых успешно завершены.
+type graphScheduler struct {
+	tasks      []GraphTask
+	mtx        sync.Mutex
+	pending    []int         // количество незавершенных зависимостей задачи
+	dependents [][]int       // задачи, зависящие от задачи
+	ready      []int         // задачи, готовые к запуску
+	running    int           // задачи, переданные в работу и еще не завершенные
+	done       []bool        // задача завершена
+	errs       []TaskError   // ошибки завершенных задач
+	notify     chan struct{} // сигнал о завершении задачи
+}
+
+func newGraphScheduler(tasks []GraphTask) *graphScheduler {
+	s := &graphScheduler{
+		tasks:      tasks,
+		pending:    make([]int, len(tasks)),
+		dependents: make([][]int, len(tasks)),
+		done:       make([]bool, len(tasks)),
+		notify:     make(chan struct{}, 1),
+	}
+
+	for i, t := range tasks {
+		s.pending[i] = len(t.Deps)
+		for _, d := range t.Deps {
+			s.dependents[d] = append(s.dependents[d], i)
+		}
+		if len(t.Deps) == 0 {
+			s.ready = append(s.ready, i)
+		}
+	}
+
+	return s
+}
+
+// передавать готовые задачи в работу, пока они не закончатся.
+func (s *graphScheduler) produce(ctx context.Context, send func(job) bool) {
+	for {
+		s.mtx.Lock()
+		if len(s.ready) > 0 {
+			idx := s.ready[0]
+			s.ready = s.ready[1:]
+			s.running++
+			s.mtx.Unlock()
+
+			if !send(job{idx: idx, task: s.tasks[idx].Task}) {
+				return
+			}
+			continue
+		}
+
+		finished := s.running == 0 // новых готовых задач не появится
+		s.mtx.Unlock()
+		if finished {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.notify:
+		}
+	}
+}
+
+// учесть завершение задачи. Задачи, зависящие от завершившейся ошибкой, не станут готовыми.
+func (s *graphScheduler) complete(res jobResult) {
+	s.mtx.Lock()
+	s.running--
+	s.done[res.idx] = true
+	if res.err != nil {
+		s.errs = append(s.errs, TaskError{Index: res.idx, Err: res.err})
+	} else {
+		for _, d := range s.dependents[res.idx] {
+			s.pending[d]--
+			if s.pending[d] == 0 {
+				s.ready = append(s.ready, d)
+			}
+		}
+	}
+	s.mtx.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default: // сигнал уже ожидает обработки
+	}
+}
+
+// номера незапускавшихся задач.
+func (s *graphScheduler) skipped() []int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var skipped []int
+	for i, done := range s.done {
+		if !done {
+			skipped = append(skipped, i)
+		}
+	}
+
+	return skipped
+}
+
+// ошибки завершенных задач в порядке их номеров.
+func (s *graphScheduler) taskErrors() []TaskError {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return newRunError(s.errs, false).Errors
+}
@@ -0,0 +1,195 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestRunGraph(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("dependencies order", func(t *testing.T) {
+		mtx := sync.Mutex{}
+		var order []int
+		record := func(i int) TaskCtx {
+			return func(context.Context) error {
+				time.Sleep(time.Millisecond)
+				mtx.Lock()
+				order = append(order, i)
+				mtx.Unlock()
+				return nil
+			}
+		}
+
+		// 0 -> 2 <- 1, 2 -> 3, 4 независима
+		tasks := []GraphTask{
+			{Task: record(0)},
+			{Task: record(1)},
+			{Task: record(2), Deps: []int{0, 1}},
+			{Task: record(3), Deps: []int{2}},
+			{Task: record(4)},
+		}
+
+		err := RunGraph(context.Background(), tasks, Options{Workers: 3, MaxErrors: 1})
+		require.NoError(t, err)
+		require.Len(t, order, len(tasks))
+
+		pos := make(map[int]int, len(order))
+		for p, i := range order {
+			pos[i] = p
+		}
+		require.Less(t, pos[0], pos[2])
+		require.Less(t, pos[1], pos[2])
+		require.Less(t, pos[2], pos[3])
+	})
+
+	t.Run("independent tasks run concurrently", func(t *testing.T) {
+		var running, peak int32
+		task := func(context.Context) error {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return nil
+		}
+
+		tasks := []GraphTask{{Task: task}, {Task: task}, {Task: task}, {Task: task, Deps: []int{0, 1, 2}}}
+		err := RunGraph(context.Background(), tasks, Options{Workers: 3, MaxErrors: 1})
+		require.NoError(t, err)
+		require.Equal(t, int32(3), peak)
+	})
+
+	t.Run("failed dependency skips dependents", func(t *testing.T) {
+		var runs [5]int32
+		task := func(i int, err error) TaskCtx {
+			return func(context.Context) error {
+				atomic.AddInt32(&runs[i], 1)
+				return err
+			}
+		}
+
+		tasks := []GraphTask{
+			{Task: task(0, errTest)},
+			{Task: task(1, nil), Deps: []int{0}},
+			{Task: task(2, nil), Deps: []int{1}},
+			{Task: task(3, nil)},
+			{Task: task(4, nil), Deps: []int{3}},
+		}
+
+		err := RunGraph(context.Background(), tasks, Options{Workers: 2, MaxErrors: 2})
+
+		var re RunError
+		require.True(t, errors.As(err, &re))
+		require.False(t, re.LimitExceeded)
+		require.Equal(t, []TaskError{{Index: 0, Err: errTest}}, re.Errors)
+		require.Equal(t, []int{1, 2}, re.Skipped)
+		require.Equal(t, [5]int32{1, 0, 0, 1, 1}, runs)
+	})
+
+	t.Run("errors limit skips remaining", func(t *testing.T) {
+		var runs int32
+		tasks := make([]GraphTask, 10)
+		for i := range tasks {
+			tasks[i].Task = func(context.Context) error {
+				atomic.AddInt32(&runs, 1)
+				return errTest
+			}
+			if i > 0 {
+				tasks[i].Deps = []int{0}
+			}
+		}
+		tasks[0].Task = func(context.Context) error { return nil }
+
+		err := RunGraph(context.Background(), tasks, Options{Workers: 1, MaxErrors: 2})
+
+		var re RunError
+		require.True(t, errors.As(err, &re))
+		require.True(t, errors.Is(err, ErrErrorsLimitExceeded))
+		require.Len(t, re.Errors, 2)
+		require.Equal(t, int32(2), runs)
+		require.Equal(t, []int{3, 4, 5, 6, 7, 8, 9}, re.Skipped)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		tasks := []GraphTask{
+			{Task: func(context.Context) error {
+				cancel()
+				return nil
+			}},
+			{Task: func(context.Context) error { return nil }, Deps: []int{0}},
+		}
+
+		err := RunGraph(ctx, tasks, Options{Workers: 2, MaxErrors: 1})
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestRunGraphValidation(t *testing.T) {
+	var runs int32
+	task := func(context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	}
+
+	tests := []struct {
+		name  string
+		tasks []GraphTask
+		err   error
+		msg   string
+	}{
+		{
+			name:  "empty",
+			tasks: nil,
+			err:   ErrEmptyTasks,
+			msg:   "empty task list",
+		},
+		{
+			name:  "unknown dependency",
+			tasks: []GraphTask{{Task: task}, {Task: task, Deps: []int{2}}},
+			err:   ErrUnknownDependency,
+			msg:   "unknown dependency: task 1 depends on 2",
+		},
+		{
+			name:  "self dependency",
+			tasks: []GraphTask{{Task: task, Deps: []int{0}}},
+			err:   ErrDependencyCycle,
+			msg:   "dependency cycle: 0 -> 0",
+		},
+		{
+			name: "cycle",
+			tasks: []GraphTask{
+				{Task: task},
+				{Task: task, Deps: []int{0, 3}},
+				{Task: task, Deps: []int{1}},
+				{Task: task, Deps: []int{2}},
+			},
+			err: ErrDependencyCycle,
+			msg: "dependency cycle: 1 -> 3 -> 2 -> 1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := RunGraph(context.Background(), test.tasks, Options{Workers: 2, MaxErrors: 1})
+
+			require.ErrorIs(t, err, test.err)
+			require.Equal(t, test.msg, err.Error())
+			require.Zero(t, atomic.LoadInt32(&runs), "tasks started before validation")
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"time"
+)
+
+// выполнить задачу, преобразовав панику в PanicError.
+func safeCall(ctx context.Context, task TaskCtx) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	return task(ctx)
+}
+
+// выполнить задачу с ограничением времени.
+// Задача выполняется в отдельной горутине, чтобы зависшая задача не блокировала воркер.
+func callWithTimeout(ctx context.Context, task TaskCtx, timeout time.Duration) error {
+	taskCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1) // буфер, чтобы брошенная задача могла завершиться
+	go func() {
+		done <- safeCall(taskCtx, task)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil && ctx.Err() == nil && errors.Is(taskCtx.Err(), context.DeadlineExceeded) {
+			return TimeoutError{Timeout: timeout}
+		}
+		return err
+	case <-taskCtx.Done():
+		if err := ctx.Err(); err != nil { // отменен родительский контекст
+			return err
+		}
+		return TimeoutError{Timeout: timeout}
+	}
+}
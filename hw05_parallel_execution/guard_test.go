@@ -0,0 +1,162 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestSafeCall(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		err := safeCall(context.Background(), func(context.Context) error {
+			panic("boom")
+		})
+
+		var pe PanicError
+		require.True(t, errors.As(err, &pe))
+		require.Equal(t, "boom", pe.Value)
+		require.Contains(t, string(pe.Stack), "TestSafeCall")
+		require.Equal(t, "task panicked: boom", err.Error())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		err := safeCall(context.Background(), func(context.Context) error {
+			return errTest
+		})
+
+		require.ErrorIs(t, err, errTest)
+	})
+}
+
+func TestCallWithTimeout(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("hanging task", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		start := time.Now()
+		err := callWithTimeout(context.Background(), func(context.Context) error {
+			<-release // задача не реагирует на отмену контекста
+			return nil
+		}, 10*time.Millisecond)
+
+		require.Less(t, time.Since(start), time.Second)
+
+		var te TimeoutError
+		require.True(t, errors.As(err, &te))
+		require.Equal(t, 10*time.Millisecond, te.Timeout)
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+
+	t.Run("task respects context", func(t *testing.T) {
+		err := callWithTimeout(context.Background(), func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, 10*time.Millisecond)
+
+		var te TimeoutError
+		require.True(t, errors.As(err, &te))
+	})
+
+	t.Run("in time", func(t *testing.T) {
+		err := callWithTimeout(context.Background(), func(context.Context) error {
+			return errTest
+		}, time.Second)
+
+		require.ErrorIs(t, err, errTest)
+
+		err = callWithTimeout(context.Background(), func(context.Context) error {
+			return nil
+		}, time.Second)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("parent canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := callWithTimeout(ctx, func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, time.Second)
+
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		err := callWithTimeout(context.Background(), func(context.Context) error {
+			panic("boom")
+		}, time.Second)
+
+		var pe PanicError
+		require.True(t, errors.As(err, &pe))
+	})
+}
+
+func TestRunGuard(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("panics count as errors", func(t *testing.T) {
+		tasksCount := 20
+		tasks := make([]Task, 0, tasksCount)
+
+		var runTasksCount int32
+		for i := 0; i < tasksCount; i++ {
+			tasks = append(tasks, func() error {
+				atomic.AddInt32(&runTasksCount, 1)
+				panic(i)
+			})
+		}
+
+		workersCount, maxErrorsCount := 4, 3
+		err := Run(tasks, workersCount, maxErrorsCount)
+
+		require.Truef(t, errors.Is(err, ErrErrorsLimitExceeded), "actual err - %v", err)
+		require.LessOrEqual(t, runTasksCount, int32(workersCount+maxErrorsCount), "extra tasks were started")
+
+		var pe PanicError
+		require.True(t, errors.As(err, &pe))
+	})
+
+	t.Run("hanging tasks", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		tasksCount := 10
+		tasks := make([]TaskCtx, 0, tasksCount)
+		for i := 0; i < tasksCount; i++ {
+			if i%2 == 0 {
+				tasks = append(tasks, func(context.Context) error {
+					<-release // зависшая задача
+					return nil
+				})
+				continue
+			}
+			tasks = append(tasks, func(context.Context) error { return nil })
+		}
+
+		start := time.Now()
+		err := RunContext(context.Background(), tasks, Options{
+			Workers:     3,
+			MaxErrors:   tasksCount,
+			TaskTimeout: 10 * time.Millisecond,
+			CollectAll:  true,
+		})
+		require.Less(t, time.Since(start), time.Second)
+
+		var runErr RunError
+		require.True(t, errors.As(err, &runErr))
+		require.Len(t, runErr.Errors, tasksCount/2)
+		for _, te := range runErr.Errors {
+			require.Equal(t, 0, te.Index%2)
+			require.True(t, errors.As(te, &TimeoutError{}))
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package hw05parallelexecution
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// ограничение частоты по алгоритму "token bucket":
+// токены пополняются со скоростью rate в секунду, но не более burst.
+type tokenBucket struct {
+	mtx    sync.Mutex
+	rate   float64   // токенов в секунду
+	burst  float64   // максимальное количество токенов
+	tokens float64   // текущее количество токенов, отрицательное - токены зарезервированы
+	last   time.Time // момент последнего пополнения
+	now    func() time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+
+	b := &tokenBucket{rate: rate, burst: float64(burst), now: time.Now}
+	b.tokens = b.burst
+	b.last = b.now()
+
+	return b
+}
+
+// зарезервировать токен и вернуть время ожидания до его появления.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	now := b.now()
+	b.tokens = min(b.tokens+now.Sub(b.last).Seconds()*b.rate, b.burst)
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// вернуть неиспользованный токен.
+func (b *tokenBucket) cancel() {
+	b.mtx.Lock()
+	b.tokens = min(b.tokens+1, b.burst)
+	b.mtx.Unlock()
+}
+
+// Wait ожидает появления токена или отмены ctx.
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	delay := b.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// взвешенный семафор: суммарный вес захвативших не превышает size.
+// Ожидающие обслуживаются в порядке очереди, чтобы тяжелые задачи не голодали.
+type weightedSemaphore struct {
+	mtx     sync.Mutex
+	size    int64
+	cur     int64
+	waiters list.List // очередь ожидающих *semWaiter
+}
+
+type semWaiter struct {
+	n     int64
+	ready chan struct{} // закрывается при захвате
+}
+
+func newWeightedSemaphore(size int64) *weightedSemaphore {
+	return &weightedSemaphore{size: size}
+}
+
+// Acquire захватывает вес n, ожидая освобождения или отмены ctx.
+func (s *weightedSemaphore) Acquire(ctx context.Context, n int64) error {
+	s.mtx.Lock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		s.mtx.Unlock()
+		return nil
+	}
+
+	w := &semWaiter{n: n, ready: make(chan struct{})}
+	elem := s.waiters.PushBack(w)
+	s.mtx.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		s.mtx.Lock()
+		select {
+		case <-w.ready: // захват произошел одновременно с отменой
+			s.cur -= n
+			s.notify()
+		default:
+			isFront := s.waiters.Front() == elem
+			s.waiters.Remove(elem)
+			if isFront { // следующий в очереди может поместиться
+				s.notify()
+			}
+		}
+		s.mtx.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Release освобождает вес n.
+func (s *weightedSemaphore) Release(n int64) {
+	s.mtx.Lock()
+	s.cur -= n
+	s.notify()
+	s.mtx.Unlock()
+}
+
+// разбудить ожидающих по порядку, пока их вес помещается. Вызывается под блокировкой.
+func (s *weightedSemaphore) notify() {
+	for {
+		front := s.waiters.Front()
+		if front == nil {
+			return
+		}
+
+		w := front.Value.(*semWaiter)
+		if s.size-s.cur < w.n {
+			return
+		}
+
+		s.cur += w.n
+		s.waiters.Remove(front)
+		close(w.ready)
+	}
+}
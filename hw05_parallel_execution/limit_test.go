@@ -0,0 +1,184 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestTokenBucket(t *testing.T) {
+	t.Run("reserve", func(t *testing.T) {
+		now := time.Unix(0, 0)
+		b := newTokenBucket(10, 2)
+		b.now = func() time.Time { return now }
+		b.last = now
+
+		require.Equal(t, time.Duration(0), b.reserve())
+		require.Equal(t, time.Duration(0), b.reserve())
+		require.Equal(t, 100*time.Millisecond, b.reserve())
+		require.Equal(t, 200*time.Millisecond, b.reserve())
+
+		now = now.Add(time.Second) // токены пополнены, но не более burst
+		require.Equal(t, time.Duration(0), b.reserve())
+		require.Equal(t, time.Duration(0), b.reserve())
+		require.Equal(t, 100*time.Millisecond, b.reserve())
+	})
+
+	t.Run("wait canceled", func(t *testing.T) {
+		b := newTokenBucket(1, 1)
+		require.NoError(t, b.Wait(context.Background()))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		require.ErrorIs(t, b.Wait(ctx), context.DeadlineExceeded)
+		require.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+}
+
+func TestWeightedSemaphore(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("fifo", func(t *testing.T) {
+		s := newWeightedSemaphore(3)
+		require.NoError(t, s.Acquire(context.Background(), 2))
+
+		heavy := make(chan struct{})
+		go func() {
+			require.NoError(t, s.Acquire(context.Background(), 3))
+			close(heavy)
+		}()
+		require.Eventually(t, func() bool {
+			s.mtx.Lock()
+			defer s.mtx.Unlock()
+			return s.waiters.Len() == 1
+		}, time.Second, time.Millisecond)
+
+		// легкая задача помещается, но ждет своей очереди за тяжелой
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, s.Acquire(ctx, 1), context.DeadlineExceeded)
+
+		s.Release(2)
+		<-heavy
+		s.Release(3)
+		require.Equal(t, int64(0), s.cur)
+	})
+
+	t.Run("cancel front waiter", func(t *testing.T) {
+		s := newWeightedSemaphore(2)
+		require.NoError(t, s.Acquire(context.Background(), 1))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		heavy := make(chan error)
+		go func() {
+			heavy <- s.Acquire(ctx, 2)
+		}()
+		light := make(chan error)
+		go func() {
+			require.Eventually(t, func() bool {
+				s.mtx.Lock()
+				defer s.mtx.Unlock()
+				return s.waiters.Len() == 1
+			}, time.Second, time.Millisecond)
+			light <- s.Acquire(context.Background(), 1)
+		}()
+
+		require.Eventually(t, func() bool {
+			s.mtx.Lock()
+			defer s.mtx.Unlock()
+			return s.waiters.Len() == 2
+		}, time.Second, time.Millisecond)
+
+		cancel() // после отмены первого ожидающего следующий получает вес
+		require.ErrorIs(t, <-heavy, context.Canceled)
+		require.NoError(t, <-light)
+		require.Equal(t, int64(2), s.cur)
+	})
+}
+
+func TestRunLimits(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("rate limit", func(t *testing.T) {
+		tasks := make([]TaskCtx, 10)
+		for i := range tasks {
+			tasks[i] = func(context.Context) error { return nil }
+		}
+
+		start := time.Now()
+		err := RunContext(context.Background(), tasks, Options{
+			Workers: 5, MaxErrors: 1, RateLimit: 100, RateBurst: 1,
+		})
+		require.NoError(t, err)
+		// первый запуск сразу, остальные 9 - с интервалом 10ms
+		require.GreaterOrEqual(t, time.Since(start), 80*time.Millisecond)
+	})
+
+	t.Run("rate limit counts retries", func(t *testing.T) {
+		var runs int32
+		tasks := []TaskCtx{func(context.Context) error {
+			atomic.AddInt32(&runs, 1)
+			return errTest
+		}}
+
+		start := time.Now()
+		err := RunContext(context.Background(), tasks, Options{
+			Workers: 1, MaxErrors: 1, RateLimit: 50, Retry: RetryPolicy{MaxAttempts: 3},
+		})
+		require.ErrorIs(t, err, errTest)
+		require.Equal(t, int32(3), runs)
+		require.GreaterOrEqual(t, time.Since(start), 35*time.Millisecond)
+	})
+
+	t.Run("weighted concurrency", func(t *testing.T) {
+		weights := []int64{3, 1, 1, 2, 5, 1, 1, 1, 2, 4}
+		var cur, peak int64
+		tasks := make([]TaskCtx, len(weights))
+		for i, w := range weights {
+			tasks[i] = func(context.Context) error {
+				n := atomic.AddInt64(&cur, min(w, 4))
+				for {
+					p := atomic.LoadInt64(&peak)
+					if n <= p || atomic.CompareAndSwapInt64(&peak, p, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt64(&cur, -min(w, 4))
+				return nil
+			}
+		}
+
+		err := RunContext(context.Background(), tasks, Options{
+			Workers: len(tasks), MaxErrors: 1, MaxWeight: 4,
+			TaskWeight: func(idx int) int64 { return weights[idx] },
+		})
+		require.NoError(t, err)
+		require.LessOrEqual(t, peak, int64(4))
+		require.Positive(t, peak)
+	})
+
+	t.Run("cancel while waiting", func(t *testing.T) {
+		var runs int32
+		tasks := make([]TaskCtx, 100)
+		for i := range tasks {
+			tasks[i] = func(context.Context) error {
+				atomic.AddInt32(&runs, 1)
+				return nil
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+		defer cancel()
+
+		err := RunContext(ctx, tasks, Options{Workers: 10, MaxErrors: 1, RateLimit: 100})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Less(t, int(runs), len(tasks))
+	})
+}
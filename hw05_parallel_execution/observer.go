@@ -0,0 +1,167 @@
+package hw05parallelexecution
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Observer - наблюдатель за ходом выполнения задач.
+// Методы вызываются из горутин воркеров конкурентно и не должны надолго блокироваться.
+type Observer interface {
+	TaskStarted(idx int)                         // задача запущена
+	TaskSucceeded(idx int)                       // задача завершена успешно
+	TaskFailed(idx int, err error, errCount int) // задача завершена ошибкой, errCount - текущее кол-во ошибок
+	TaskSkipped(idx int)                         // задача не будет запущена
+	Progress(p Progress)                         // периодический снимок хода выполнения
+}
+
+// NopObserver - наблюдатель, игнорирующий все события.
+// Предназначен для встраивания, чтобы реализовывать только нужные методы Observer.
+type NopObserver struct{}
+
+func (NopObserver) TaskStarted(int)            {}
+func (NopObserver) TaskSucceeded(int)          {}
+func (NopObserver) TaskFailed(int, error, int) {}
+func (NopObserver) TaskSkipped(int)            {}
+func (NopObserver) Progress(Progress)          {}
+
+// Progress - снимок хода выполнения задач.
+type Progress struct {
+	Total     int           // общее количество задач, 0 - неизвестно
+	Started   int           // запущено задач
+	Succeeded int           // завершено успешно
+	Failed    int           // завершено ошибкой
+	Skipped   int           // пропущено задач
+	Elapsed   time.Duration // время с начала выполнения
+	Done      bool          // выполнение завершено, снимок последний
+}
+
+// Running - количество выполняющихся задач.
+func (p Progress) Running() int {
+	return p.Started - p.Succeeded - p.Failed
+}
+
+// отслеживание хода выполнения для наблюдателя. Методы допускают вызов для nil.
+type tracker struct {
+	obs   Observer
+	total int
+	start time.Time
+
+	counts struct {
+		started, succeeded, failed, skipped atomic.Int64
+	}
+
+	reported []atomic.Bool // о задаче сообщалось наблюдателю; nil - общее количество неизвестно
+
+	stop    chan struct{} // сигнал остановки периодических снимков
+	stopped sync.WaitGroup
+}
+
+func newTracker(obs Observer, total int, interval time.Duration) *tracker {
+	t := &tracker{obs: obs, total: total, start: time.Now()}
+	if total > 0 {
+		t.reported = make([]atomic.Bool, total)
+	}
+
+	if interval > 0 {
+		t.stop = make(chan struct{})
+		t.stopped.Add(1)
+		go t.report(interval)
+	}
+
+	return t
+}
+
+// периодически передавать снимок хода выполнения до остановки.
+func (t *tracker) report(interval time.Duration) {
+	defer t.stopped.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.stop:
+			return
+		case <-ticker.C:
+			t.obs.Progress(t.snapshot(false))
+		}
+	}
+}
+
+func (t *tracker) snapshot(done bool) Progress {
+	return Progress{
+		Total:     t.total,
+		Started:   int(t.counts.started.Load()),
+		Succeeded: int(t.counts.succeeded.Load()),
+		Failed:    int(t.counts.failed.Load()),
+		Skipped:   int(t.counts.skipped.Load()),
+		Elapsed:   time.Since(t.start),
+		Done:      done,
+	}
+}
+
+// отметить, что о задаче сообщалось; false - уже сообщалось.
+func (t *tracker) markReported(idx int) bool {
+	if t.reported == nil || idx >= len(t.reported) {
+		return true
+	}
+
+	return !t.reported[idx].Swap(true)
+}
+
+func (t *tracker) started(idx int) {
+	if t == nil {
+		return
+	}
+
+	t.markReported(idx)
+	t.counts.started.Add(1)
+	t.obs.TaskStarted(idx)
+}
+
+func (t *tracker) succeeded(idx int) {
+	if t == nil {
+		return
+	}
+
+	t.counts.succeeded.Add(1)
+	t.obs.TaskSucceeded(idx)
+}
+
+func (t *tracker) failed(idx int, err error, errCount int) {
+	if t == nil {
+		return
+	}
+
+	t.counts.failed.Add(1)
+	t.obs.TaskFailed(idx, err, errCount)
+}
+
+func (t *tracker) skipped(idx int) {
+	if t == nil || !t.markReported(idx) {
+		return
+	}
+
+	t.counts.skipped.Add(1)
+	t.obs.TaskSkipped(idx)
+}
+
+// сообщить о пропуске задач, о которых не сообщалось, и передать итоговый снимок.
+func (t *tracker) finish() {
+	if t == nil {
+		return
+	}
+
+	if t.stop != nil {
+		close(t.stop)
+		t.stopped.Wait()
+	}
+
+	for idx := range t.reported {
+		t.skipped(idx)
+	}
+
+	t.obs.Progress(t.snapshot(true))
+}
@@ -0,0 +1,195 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+// наблюдатель, запоминающий события.
+type recordingObserver struct {
+	mtx       sync.Mutex
+	started   []int
+	succeeded []int
+	failed    []int
+	errCounts []int
+	skipped   []int
+	progress  []Progress
+}
+
+func (o *recordingObserver) TaskStarted(idx int) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.started = append(o.started, idx)
+}
+
+func (o *recordingObserver) TaskSucceeded(idx int) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.succeeded = append(o.succeeded, idx)
+}
+
+func (o *recordingObserver) TaskFailed(idx int, _ error, errCount int) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.failed = append(o.failed, idx)
+	o.errCounts = append(o.errCounts, errCount)
+}
+
+func (o *recordingObserver) TaskSkipped(idx int) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.skipped = append(o.skipped, idx)
+}
+
+func (o *recordingObserver) Progress(p Progress) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.progress = append(o.progress, p)
+}
+
+func (o *recordingObserver) last() Progress {
+	return o.progress[len(o.progress)-1]
+}
+
+func sorted(s []int) []int {
+	sort.Ints(s)
+	return s
+}
+
+func TestObserver(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("lifecycle", func(t *testing.T) {
+		tasks := make([]TaskCtx, 6)
+		for i := range tasks {
+			tasks[i] = func(context.Context) error {
+				if i%3 == 0 {
+					return errTest
+				}
+				return nil
+			}
+		}
+
+		obs := &recordingObserver{}
+		err := RunContext(context.Background(), tasks, Options{Workers: 3, CollectAll: true, Observer: obs})
+		require.Error(t, err)
+
+		require.Equal(t, []int{0, 1, 2, 3, 4, 5}, sorted(obs.started))
+		require.Equal(t, []int{1, 2, 4, 5}, sorted(obs.succeeded))
+		require.Equal(t, []int{0, 3}, sorted(obs.failed))
+		require.Equal(t, []int{1, 2}, sorted(obs.errCounts))
+		require.Empty(t, obs.skipped)
+
+		require.Len(t, obs.progress, 1)
+		require.Equal(t, Progress{
+			Total: 6, Started: 6, Succeeded: 4, Failed: 2, Elapsed: obs.last().Elapsed, Done: true,
+		}, obs.last())
+		require.Zero(t, obs.last().Running())
+	})
+
+	t.Run("skipped after errors limit", func(t *testing.T) {
+		tasks := make([]TaskCtx, 20)
+		for i := range tasks {
+			tasks[i] = func(context.Context) error {
+				return errTest
+			}
+		}
+
+		obs := &recordingObserver{}
+		err := RunContext(context.Background(), tasks, Options{Workers: 2, MaxErrors: 3, Observer: obs})
+		require.ErrorIs(t, err, ErrErrorsLimitExceeded)
+
+		// о каждой задаче сообщается ровно один раз: запущена или пропущена
+		all := append(append([]int{}, obs.started...), obs.skipped...)
+		require.Len(t, sorted(all), len(tasks))
+		for i, idx := range all {
+			require.Equal(t, i, idx)
+		}
+		require.Equal(t, sorted(obs.started), sorted(obs.failed))
+
+		p := obs.last()
+		require.True(t, p.Done)
+		require.Equal(t, len(tasks), p.Started+p.Skipped)
+		require.GreaterOrEqual(t, p.Failed, 3)
+	})
+
+	t.Run("graph dependents skipped", func(t *testing.T) {
+		obs := &recordingObserver{}
+		tasks := []GraphTask{
+			{Task: func(context.Context) error { return errTest }},
+			{Task: func(context.Context) error { return nil }, Deps: []int{0}},
+			{Task: func(context.Context) error { return nil }},
+		}
+
+		err := RunGraph(context.Background(), tasks, Options{Workers: 2, MaxErrors: 2, Observer: obs})
+		require.Error(t, err)
+		require.Equal(t, []int{1}, obs.skipped)
+		require.Equal(t, []int{2}, obs.succeeded)
+		require.Equal(t, []int{0}, obs.failed)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		ch := make(chan TaskCtx)
+		go func() {
+			defer close(ch)
+			for i := 0; i < 5; i++ {
+				ch <- func(context.Context) error { return nil }
+			}
+		}()
+
+		obs := &recordingObserver{}
+		err := RunChan(context.Background(), ch, Options{Workers: 2, MaxErrors: 1, Observer: obs})
+		require.NoError(t, err)
+		require.Equal(t, Progress{
+			Started: 5, Succeeded: 5, Elapsed: obs.last().Elapsed, Done: true,
+		}, obs.last())
+	})
+
+	t.Run("periodic progress", func(t *testing.T) {
+		release := make(chan struct{})
+		tasks := []TaskCtx{
+			func(context.Context) error { return nil },
+			func(context.Context) error {
+				<-release
+				return nil
+			},
+		}
+
+		obs := &recordingObserver{}
+		done := make(chan error)
+		go func() {
+			done <- RunContext(context.Background(), tasks, Options{
+				Workers: 2, MaxErrors: 1, Observer: obs, ProgressInterval: time.Millisecond,
+			})
+		}()
+
+		require.Eventually(t, func() bool {
+			obs.mtx.Lock()
+			defer obs.mtx.Unlock()
+			return len(obs.progress) > 0 && obs.last().Succeeded == 1
+		}, time.Second, time.Millisecond)
+
+		obs.mtx.Lock()
+		p := obs.last()
+		obs.mtx.Unlock()
+		require.False(t, p.Done)
+		require.Equal(t, 1, p.Running())
+
+		close(release)
+		require.NoError(t, <-done)
+		require.True(t, obs.last().Done)
+	})
+
+	t.Run("nop observer", func(t *testing.T) {
+		obs := struct{ NopObserver }{}
+		err := RunContext(context.Background(), []TaskCtx{func(context.Context) error { return nil }},
+			Options{Workers: 1, MaxErrors: 1, Observer: obs})
+		require.NoError(t, err)
+	})
+}
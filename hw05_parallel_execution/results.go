@@ -0,0 +1,69 @@
+package hw05parallelexecution
+
+import "context"
+
+// ResultTask - задача, возвращающая результат типа T.
+type ResultTask[T any] func(ctx context.Context) (T, error)
+
+// Result - результат выполнения задачи с ее номером.
+type Result[T any] struct {
+	Index int   // номер задачи
+	Value T     // результат задачи
+	Err   error // ошибка задачи
+}
+
+// RunResults выполняет задачи в n горутинах и возвращает их результаты в порядке задач.
+// Ограничение на количество ошибок m и возвращаемая ошибка те же, что у RunContext.
+// Для задач, завершившихся ошибкой или не запущенных, результат - нулевое значение T.
+func RunResults[T any](ctx context.Context, tasks []ResultTask[T], n, m int) ([]T, error) {
+	results := make([]T, len(tasks))
+
+	ctxTasks := make([]TaskCtx, len(tasks))
+	for i, task := range tasks {
+		ctxTasks[i] = func(ctx context.Context) error {
+			v, err := task(ctx)
+			if err == nil {
+				results[i] = v
+			}
+			return err
+		}
+	}
+
+	err := RunContext(ctx, ctxTasks, Options{Workers: n, MaxErrors: m})
+
+	return results, err
+}
+
+// StreamResults выполняет задачи в n горутинах и передает их результаты в канал по мере завершения.
+// Канал закрывается после завершения всех задач, после чего функция wait возвращает итоговую ошибку,
+// такую же, как у RunContext. Канал необходимо читать до закрытия.
+func StreamResults[T any](ctx context.Context, tasks []ResultTask[T], n, m int) (<-chan Result[T], func() error) {
+	out := make(chan Result[T])
+	done := make(chan struct{})
+	var runErr error
+
+	ctxTasks := make([]TaskCtx, len(tasks))
+	for i, task := range tasks {
+		ctxTasks[i] = func(ctx context.Context) error {
+			var v T
+			err := safeCall(ctx, func(ctx context.Context) (err error) { // паника тоже передается как результат
+				v, err = task(ctx)
+				return err
+			})
+			out <- Result[T]{Index: i, Value: v, Err: err}
+			return err
+		}
+	}
+
+	go func() {
+		defer close(done)
+		defer close(out)
+
+		runErr = RunContext(ctx, ctxTasks, Options{Workers: n, MaxErrors: m})
+	}()
+
+	return out, func() error {
+		<-done
+		return runErr
+	}
+}
@@ -0,0 +1,120 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestRunResults(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("results in input order", func(t *testing.T) {
+		tasksCount := 50
+		tasks := make([]ResultTask[string], 0, tasksCount)
+
+		for i := 0; i < tasksCount; i++ {
+			tasks = append(tasks, func(context.Context) (string, error) {
+				time.Sleep(time.Millisecond * time.Duration(rand.Intn(10)))
+				return strconv.Itoa(i), nil
+			})
+		}
+
+		results, err := RunResults(context.Background(), tasks, 10, 1)
+
+		require.NoError(t, err)
+		require.Len(t, results, tasksCount)
+		for i, r := range results {
+			require.Equal(t, strconv.Itoa(i), r)
+		}
+	})
+
+	t.Run("errors limit", func(t *testing.T) {
+		tasks := []ResultTask[int]{
+			func(context.Context) (int, error) { return 0, errTest },
+			func(context.Context) (int, error) { return 1, nil },
+		}
+
+		results, err := RunResults(context.Background(), tasks, 1, 1)
+
+		require.Truef(t, errors.Is(err, ErrErrorsLimitExceeded), "actual err - %v", err)
+		require.True(t, errors.Is(err, errTest))
+		require.Equal(t, []int{0, 0}, results) // вторая задача не запускалась
+	})
+
+	t.Run("empty tasks", func(t *testing.T) {
+		results, err := RunResults[int](context.Background(), nil, 1, 1)
+
+		require.Truef(t, errors.Is(err, ErrEmptyTasks), "actual err - %v", err)
+		require.Empty(t, results)
+	})
+}
+
+func TestStreamResults(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("all results", func(t *testing.T) {
+		tasksCount := 50
+		tasks := make([]ResultTask[int], 0, tasksCount)
+
+		for i := 0; i < tasksCount; i++ {
+			tasks = append(tasks, func(context.Context) (int, error) {
+				time.Sleep(time.Millisecond * time.Duration(rand.Intn(10)))
+				return i * i, nil
+			})
+		}
+
+		out, wait := StreamResults(context.Background(), tasks, 10, 1)
+
+		indexes := make([]int, 0, tasksCount)
+		for r := range out {
+			require.NoError(t, r.Err)
+			require.Equal(t, r.Index*r.Index, r.Value)
+			indexes = append(indexes, r.Index)
+		}
+		require.NoError(t, wait())
+
+		sort.Ints(indexes)
+		for i, idx := range indexes {
+			require.Equal(t, i, idx)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tasksCount := 20
+		tasks := make([]ResultTask[int], 0, tasksCount)
+
+		for i := 0; i < tasksCount; i++ {
+			tasks = append(tasks, func(context.Context) (int, error) {
+				if i == 3 {
+					panic("boom")
+				}
+				return 0, fmt.Errorf("error from task %d", i)
+			})
+		}
+
+		workersCount, maxErrorsCount := 2, 4
+		out, wait := StreamResults(context.Background(), tasks, workersCount, maxErrorsCount)
+
+		count := 0
+		for r := range out {
+			require.Error(t, r.Err)
+			if r.Index == 3 {
+				require.True(t, errors.As(r.Err, &PanicError{}))
+			}
+			count++
+		}
+
+		err := wait()
+		require.Truef(t, errors.Is(err, ErrErrorsLimitExceeded), "actual err - %v", err)
+		require.LessOrEqual(t, count, workersCount+maxErrorsCount)
+	})
+}
@@ -0,0 +1,86 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Backoff - задержка перед повтором задачи; attempt - номер повтора, начиная с 1.
+type Backoff func(attempt int) time.Duration
+
+// RetryPolicy - политика повтора задачи при ошибке.
+// В лимит ошибок засчитывается только ошибка последней попытки.
+type RetryPolicy struct {
+	MaxAttempts int                  // максимальное количество попыток, включая первую; <= 1 - без повторов
+	Backoff     Backoff              // задержка перед повтором; nil - без задержки
+	Retryable   func(err error) bool // можно ли повторить задачу после ошибки; nil - после любой
+}
+
+// FixedBackoff - одинаковая задержка перед каждым повтором.
+func FixedBackoff(delay time.Duration) Backoff {
+	return func(int) time.Duration {
+		return delay
+	}
+}
+
+// ExponentialBackoff - задержка base, удваиваемая с каждым повтором, но не более maxDelay.
+// При base <= 0 повтор выполняется без задержки.
+func ExponentialBackoff(base, maxDelay time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		if base <= 0 {
+			return 0
+		}
+
+		delay := base
+		for i := 1; i < attempt; i++ {
+			if delay > maxDelay/2 { // удвоенная задержка превысит maxDelay, проверка исключает переполнение
+				return maxDelay
+			}
+			delay *= 2
+		}
+
+		return min(delay, maxDelay)
+	}
+}
+
+// ExponentialJitterBackoff - случайная задержка от 0 до значения ExponentialBackoff.
+// Разносит во времени повторы задач, упавших одновременно.
+func ExponentialJitterBackoff(base, maxDelay time.Duration) Backoff {
+	exp := ExponentialBackoff(base, maxDelay)
+
+	return func(attempt int) time.Duration {
+		delay := exp(attempt)
+		if delay <= 0 {
+			return 0
+		}
+
+		return time.Duration(rand.Int63n(int64(delay) + 1)) //nolint:gosec // криптостойкость не требуется
+	}
+}
+
+// выполнить задачу с повторами согласно политике.
+// Ожидание перед повтором прерывается отменой ctx, возвращается ошибка последней попытки.
+func (p RetryPolicy) do(ctx context.Context, task TaskCtx) error {
+	for attempt := 1; ; attempt++ {
+		err := task(ctx)
+		if err == nil || attempt >= p.MaxAttempts || (p.Retryable != nil && !p.Retryable(err)) {
+			return err
+		}
+
+		if p.Backoff == nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			continue
+		}
+
+		timer := time.NewTimer(p.Backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestBackoff(t *testing.T) {
+	t.Run("fixed", func(t *testing.T) {
+		b := FixedBackoff(10 * time.Millisecond)
+		for attempt := 1; attempt < 5; attempt++ {
+			require.Equal(t, 10*time.Millisecond, b(attempt))
+		}
+	})
+
+	t.Run("exponential", func(t *testing.T) {
+		b := ExponentialBackoff(10*time.Millisecond, 100*time.Millisecond)
+
+		require.Equal(t, 10*time.Millisecond, b(1))
+		require.Equal(t, 20*time.Millisecond, b(2))
+		require.Equal(t, 40*time.Millisecond, b(3))
+		require.Equal(t, 80*time.Millisecond, b(4))
+		require.Equal(t, 100*time.Millisecond, b(5))
+		require.Equal(t, 100*time.Millisecond, b(100))
+
+		huge := ExponentialBackoff(time.Hour, time.Duration(math.MaxInt64))
+		require.Equal(t, time.Duration(math.MaxInt64), huge(100)) // без переполнения
+
+		zero := ExponentialBackoff(0, time.Second)
+		for attempt := 1; attempt < 5; attempt++ {
+			require.Zero(t, zero(attempt))
+		}
+	})
+
+	t.Run("exponential with jitter", func(t *testing.T) {
+		b := ExponentialJitterBackoff(10*time.Millisecond, 100*time.Millisecond)
+
+		for i := 0; i < 100; i++ {
+			require.LessOrEqual(t, b(1), 10*time.Millisecond)
+			require.LessOrEqual(t, b(3), 40*time.Millisecond)
+			require.GreaterOrEqual(t, b(3), time.Duration(0))
+			require.LessOrEqual(t, b(10), 100*time.Millisecond)
+		}
+
+		require.Zero(t, ExponentialJitterBackoff(0, 0)(1))
+	})
+}
+
+func TestRetryPolicy(t *testing.T) {
+	errTemporary := errors.New("temporary error")
+	errPermanent := errors.New("permanent error")
+
+	// задача, завершающаяся ошибками failures раз подряд.
+	flaky := func(failures int32, err error, calls *int32) TaskCtx {
+		return func(context.Context) error {
+			if atomic.AddInt32(calls, 1) <= failures {
+				return err
+			}
+			return nil
+		}
+	}
+
+	t.Run("success after retries", func(t *testing.T) {
+		var calls int32
+		p := RetryPolicy{MaxAttempts: 3, Backoff: FixedBackoff(time.Millisecond)}
+
+		err := p.do(context.Background(), flaky(2, errTemporary, &calls))
+
+		require.NoError(t, err)
+		require.Equal(t, int32(3), calls)
+	})
+
+	t.Run("attempts exhausted", func(t *testing.T) {
+		var calls int32
+		p := RetryPolicy{MaxAttempts: 3}
+
+		err := p.do(context.Background(), flaky(5, errTemporary, &calls))
+
+		require.ErrorIs(t, err, errTemporary)
+		require.Equal(t, int32(3), calls)
+	})
+
+	t.Run("not retryable", func(t *testing.T) {
+		var calls int32
+		p := RetryPolicy{
+			MaxAttempts: 3,
+			Retryable: func(err error) bool {
+				return !errors.Is(err, errPermanent)
+			},
+		}
+
+		err := p.do(context.Background(), flaky(5, errPermanent, &calls))
+
+		require.ErrorIs(t, err, errPermanent)
+		require.Equal(t, int32(1), calls)
+	})
+
+	t.Run("zero policy", func(t *testing.T) {
+		var calls int32
+
+		err := RetryPolicy{}.do(context.Background(), flaky(5, errTemporary, &calls))
+
+		require.ErrorIs(t, err, errTemporary)
+		require.Equal(t, int32(1), calls)
+	})
+
+	t.Run("cancel during backoff", func(t *testing.T) {
+		var calls int32
+		p := RetryPolicy{MaxAttempts: 3, Backoff: FixedBackoff(time.Hour)}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err := p.do(ctx, flaky(5, errTemporary, &calls))
+
+		require.ErrorIs(t, err, errTemporary)
+		require.Equal(t, int32(1), calls)
+		require.Less(t, time.Since(start), time.Second)
+	})
+}
+
+func TestRunRetry(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	errTemporary := errors.New("temporary error")
+
+	t.Run("transient failures do not count", func(t *testing.T) {
+		tasksCount := 20
+		tasks := make([]TaskCtx, 0, tasksCount)
+
+		var attempts int32
+		for i := 0; i < tasksCount; i++ {
+			var calls int32
+			tasks = append(tasks, func(context.Context) error {
+				atomic.AddInt32(&attempts, 1)
+				if atomic.AddInt32(&calls, 1) < 3 { // каждая задача дважды падает
+					return errTemporary
+				}
+				return nil
+			})
+		}
+
+		err := RunContext(context.Background(), tasks, Options{
+			Workers:   5,
+			MaxErrors: 1,
+			Retry:     RetryPolicy{MaxAttempts: 3, Backoff: ExponentialJitterBackoff(time.Millisecond, 5*time.Millisecond)},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, int32(tasksCount*3), attempts)
+	})
+
+	t.Run("final failures count", func(t *testing.T) {
+		tasksCount := 20
+		tasks := make([]TaskCtx, 0, tasksCount)
+
+		var attempts int32
+		for i := 0; i < tasksCount; i++ {
+			tasks = append(tasks, func(context.Context) error {
+				atomic.AddInt32(&attempts, 1)
+				return errTemporary
+			})
+		}
+
+		workersCount, maxErrorsCount := 2, 3
+		err := RunContext(context.Background(), tasks, Options{
+			Workers:   workersCount,
+			MaxErrors: maxErrorsCount,
+			Retry:     RetryPolicy{MaxAttempts: 2},
+		})
+
+		require.Truef(t, errors.Is(err, ErrErrorsLimitExceeded), "actual err - %v", err)
+		require.LessOrEqual(t, attempts, int32((workersCount+maxErrorsCount)*2), "extra tasks were started")
+	})
+}
@@ -1,8 +1,11 @@
 package hw05parallelexecution
 
 import (
+	"context"
 	"errors"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 var (
@@ -12,73 +15,299 @@ var (
 
 type Task func() error
 
+// TaskCtx - задача, получающая контекст выполнения.
+// Контекст отменяется при отмене родительского контекста или превышении лимита ошибок.
+type TaskCtx func(ctx context.Context) error
+
+// Options - параметры выполнения задач.
+type Options struct {
+	Workers int // количество одновременно выполняемых задач
+	// MaxErrors - количество ошибок, после которого выполнение прекращается.
+	// При <= 0 задачи не запускаются и сразу возвращается ErrErrorsLimitExceeded, если не задан CollectAll.
+	MaxErrors int
+
+	// CollectAll - выполнить все задачи независимо от количества ошибок, MaxErrors не учитывается.
+	// Если были ошибки, возвращается RunError со всеми ошибками.
+	CollectAll bool
+
+	Retry RetryPolicy // политика повтора задач при ошибке
+
+	// TaskTimeout - время на выполнение одной попытки задачи; <= 0 - без ограничения.
+	// По истечении времени возвращается TimeoutError, а задача, не отреагировавшая на отмену контекста,
+	// продолжает выполняться в отдельной горутине, не задерживая завершение Run.
+	TaskTimeout time.Duration
+
+	// RateLimit - максимальное количество запусков задач в секунду для всех воркеров вместе; <= 0 - без ограничения.
+	// Учитывается каждая попытка задачи, в том числе повторная.
+	RateLimit float64
+	// RateBurst - количество запусков, допустимых подряд без ожидания; < 1 - один.
+	RateBurst int
+
+	// MaxWeight - максимальный суммарный вес одновременно выполняемых задач; <= 0 - без ограничения.
+	MaxWeight int64
+	// TaskWeight - вес задачи с номером idx; nil - вес каждой задачи равен 1.
+	// Вес больше MaxWeight уменьшается до MaxWeight.
+	TaskWeight func(idx int) int64
+
+	// Observer - наблюдатель за ходом выполнения; nil - без наблюдения.
+	Observer Observer
+	// ProgressInterval - период вызова Observer.Progress; <= 0 - только по завершении выполнения.
+	ProgressInterval time.Duration
+}
+
+// задача с ее номером в источнике задач.
+type job struct {
+	idx  int
+	task TaskCtx
+}
+
+// результат выполнения задачи.
+type jobResult struct {
+	idx int
+	err error
+}
+
 // Run Tasks with channels.
 func Run(tasks []Task, n, m int) error {
+	ctxTasks := make([]TaskCtx, len(tasks))
+	for i, task := range tasks {
+		ctxTasks[i] = func(context.Context) error {
+			return task()
+		}
+	}
+
+	return RunContext(context.Background(), ctxTasks, Options{Workers: n, MaxErrors: m})
+}
+
+// RunContext выполняет задачи в opts.Workers горутинах.
+// Выполнение прекращается при отмене ctx или после opts.MaxErrors ошибок,
+// при этом отменяется контекст уже выполняющихся задач.
+// При превышении лимита ошибок возвращается RunError, соответствующая ErrErrorsLimitExceeded.
+func RunContext(ctx context.Context, tasks []TaskCtx, opts Options) error {
 	if len(tasks) == 0 {
 		return ErrEmptyTasks
 	}
 
-	if m <= 0 {
+	if len(tasks) < opts.Workers { // если кол-во задач меньше кол-ва воркеров, ограничиваем кол-во воркеров
+		opts.Workers = len(tasks)
+	}
+
+	return run(ctx, source{
+		produce: func(_ context.Context, send func(job) bool) {
+			for i, task := range tasks {
+				if !send(job{idx: i, task: task}) {
+					return
+				}
+			}
+		},
+		total: len(tasks),
+	}, opts)
+}
+
+// источник задач: передает задачи функции send, пока она возвращает true.
+// Возврат из функции означает, что задачи закончились.
+type producer func(ctx context.Context, send func(job) bool)
+
+// получатель результатов задач. Вызывается в горутине run и не должен блокироваться.
+type completer func(res jobResult)
+
+// описание источника задач для run.
+type source struct {
+	produce  producer
+	complete completer // получатель результатов задач, nil - не нужен
+	total    int       // общее количество задач, 0 - неизвестно
+}
+
+// run выполняет задачи, получаемые от src.produce, пока они не закончатся,
+// не будет превышен лимит ошибок или не будет отменен ctx.
+// О завершении каждой запущенной задачи сообщается src.complete, если он задан.
+// Возврат происходит только после завершения всех запущенных задач.
+func run(parent context.Context, src source, opts Options) error {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	ex := newExecutor(opts, src.total, cancel)
+	defer ex.finish()
+
+	if opts.MaxErrors <= 0 && !opts.CollectAll {
 		return ErrErrorsLimitExceeded
 	}
 
-	if len(tasks) < n { // если кол-во задач меньше кол-ва воркеров, ограничиваем кол-во воркеров
-		n = len(tasks)
+	workers := opts.Workers
+	if workers < 1 {
+		workers = 1
 	}
 
-	tasksCh := make(chan Task)       // канал задач.
-	stopErrCh := make(chan struct{}) // сигнальный канал.
-	errorCh := make(chan error)      // канал ошибок.
+	jobsCh := make(chan job)         // канал задач.
+	resultCh := make(chan jobResult) // канал результатов.
 	wg := sync.WaitGroup{}
-	wgErr := sync.WaitGroup{}
 
-	defer func() {
-		close(tasksCh)
-		close(stopErrCh)
-		wg.Wait()
-		close(errorCh)
-		wgErr.Wait()
+	// запускаем источник задач, после отмены ctx задачи из него не запрашиваются
+	go func() {
+		defer close(jobsCh)
+
+		src.produce(ctx, func(j job) bool {
+			if ctx.Err() != nil { // select выбирает случайно, если свободный воркер уже ожидает задачу
+				return false
+			}
+
+			select {
+			case <-ctx.Done():
+				return false
+			case jobsCh <- j:
+				return true
+			}
+		})
 	}()
 
 	// запускаем воркеры
-	for i := 0; i < n; i++ {
+	for w := 0; w < workers; w++ {
 		wg.Add(1)
-		go doWork(&wg, tasksCh, errorCh)
+		go doWork(ctx, &wg, jobsCh, resultCh, ex)
 	}
 
-	wgErr.Add(1)
-	go func(wg *sync.WaitGroup) {
-		defer wg.Done()
+	go func() {
+		wg.Wait()
+		close(resultCh)
+	}()
 
-		errCount := 0 // текущее кол-во ошибок
-		for range errorCh {
-			errCount++
-			if errCount == m {
-				stopErrCh <- struct{}{}
-			}
+	var errs []TaskError // ошибки задач
+	for res := range resultCh {
+		if src.complete != nil {
+			src.complete(res)
 		}
-	}(&wgErr)
-
-	for _, task := range tasks {
-		select {
-		case <-stopErrCh:
-			return ErrErrorsLimitExceeded
-		case tasksCh <- task:
+		if res.err == nil {
+			continue
 		}
+
+		errs = append(errs, TaskError{Index: res.idx, Err: res.err})
 	}
 
-	return nil
+	return runResult(parent, errs, opts)
+}
+
+// итог выполнения задач.
+func runResult(ctx context.Context, errs []TaskError, opts Options) error {
+	switch {
+	case opts.CollectAll && len(errs) > 0:
+		return newRunError(errs, false)
+	case !opts.CollectAll && len(errs) >= opts.MaxErrors:
+		return newRunError(errs, true)
+	default:
+		return ctx.Err()
+	}
 }
 
-func doWork(wg *sync.WaitGroup, tasks <-chan Task, errs chan<- error) {
+func doWork(ctx context.Context, wg *sync.WaitGroup, jobs <-chan job, results chan<- jobResult, ex *executor) {
 	defer func() {
 		wg.Done()
 	}()
 
-	for task := range tasks {
-		err := task()
-		if err != nil {
-			errs <- err
+	for j := range jobs {
+		if ctx.Err() != nil { // выполнение прекращено, оставшиеся задачи не запускаем
+			ex.skip(j.idx)
+			continue
+		}
+
+		started, err := ex.exec(ctx, j)
+		if !started { // выполнение прекращено во время ожидания запуска
+			ex.skip(j.idx)
+			continue
+		}
+		results <- jobResult{idx: j.idx, err: err}
+	}
+}
+
+// исполнитель задач, общий для всех воркеров.
+type executor struct {
+	opts     Options
+	limiter  *tokenBucket       // ограничение частоты запусков, nil - без ограничения
+	sem      *weightedSemaphore // ограничение суммарного веса задач, nil - без ограничения
+	errCount atomic.Int64       // текущее кол-во ошибок
+	stop     context.CancelFunc // прекращение выполнения при превышении лимита ошибок
+	tracker  *tracker           // отслеживание хода выполнения, nil - без наблюдателя
+}
+
+func newExecutor(opts Options, total int, stop context.CancelFunc) *executor {
+	ex := &executor{opts: opts, stop: stop}
+	if opts.RateLimit > 0 {
+		ex.limiter = newTokenBucket(opts.RateLimit, opts.RateBurst)
+	}
+	if opts.MaxWeight > 0 {
+		ex.sem = newWeightedSemaphore(opts.MaxWeight)
+	}
+	if opts.Observer != nil {
+		ex.tracker = newTracker(opts.Observer, total, opts.ProgressInterval)
+	}
+
+	return ex
+}
+
+// выполнить задачу с учетом параметров: веса, частоты запусков, повторов, ограничения времени, перехвата паники.
+// started - задача была запущена хотя бы раз.
+func (ex *executor) exec(ctx context.Context, j job) (started bool, err error) {
+	if ex.sem != nil {
+		weight := ex.weight(j.idx)
+		if ex.sem.Acquire(ctx, weight) != nil {
+			return false, nil
 		}
+		defer ex.sem.Release(weight)
 	}
+
+	err = ex.opts.Retry.do(ctx, func(ctx context.Context) error {
+		if ex.limiter != nil {
+			if werr := ex.limiter.Wait(ctx); werr != nil {
+				if !started {
+					return werr
+				}
+				return err // ошибка предыдущей попытки
+			}
+		}
+
+		if !started {
+			started = true
+			ex.tracker.started(j.idx)
+		}
+		if ex.opts.TaskTimeout > 0 {
+			err = callWithTimeout(ctx, j.task, ex.opts.TaskTimeout)
+		} else {
+			err = safeCall(ctx, j.task)
+		}
+		return err
+	})
+
+	switch {
+	case !started:
+	case err != nil:
+		// ошибки считаются в воркере до передачи результата,
+		// чтобы после превышения лимита воркер уже не взял следующую задачу
+		errCount := ex.errCount.Add(1)
+		if errCount == int64(ex.opts.MaxErrors) && !ex.opts.CollectAll {
+			ex.stop()
+		}
+		ex.tracker.failed(j.idx, err, int(errCount))
+	default:
+		ex.tracker.succeeded(j.idx)
+	}
+
+	return started, err
+}
+
+// учесть задачу, которая не будет запущена.
+func (ex *executor) skip(idx int) {
+	ex.tracker.skipped(idx)
+}
+
+// завершить выполнение: задачи, о которых не сообщалось, считаются пропущенными.
+func (ex *executor) finish() {
+	ex.tracker.finish()
+}
+
+// вес задачи.
+func (ex *executor) weight(idx int) int64 {
+	if ex.opts.TaskWeight == nil {
+		return 1
+	}
+
+	return max(min(ex.opts.TaskWeight(idx), ex.opts.MaxWeight), 0)
 }
@@ -1,6 +1,7 @@
 package hw05parallelexecution
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/rand"
@@ -155,3 +156,175 @@ func TestRun(t *testing.T) {
 		require.Truef(t, errors.Is(err, ErrErrorsLimitExceeded), "actual err - %v", err)
 	})
 }
+
+func TestRunContext(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("tasks without errors", func(t *testing.T) {
+		tasksCount := 50
+		tasks := make([]TaskCtx, 0, tasksCount)
+
+		var runTasksCount int32
+
+		for i := 0; i < tasksCount; i++ {
+			tasks = append(tasks, func(context.Context) error {
+				atomic.AddInt32(&runTasksCount, 1)
+				return nil
+			})
+		}
+
+		err := RunContext(context.Background(), tasks, Options{Workers: 5, MaxErrors: 1})
+
+		require.NoError(t, err)
+		require.Equal(t, int32(tasksCount), runTasksCount, "not all tasks were completed")
+	})
+
+	t.Run("parent context canceled", func(t *testing.T) {
+		tasksCount := 50
+		tasks := make([]TaskCtx, 0, tasksCount)
+
+		var runTasksCount, canceledCount int32
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		for i := 0; i < tasksCount; i++ {
+			tasks = append(tasks, func(ctx context.Context) error {
+				if atomic.AddInt32(&runTasksCount, 1) == 5 {
+					cancel()
+				}
+				<-ctx.Done() // задача завершается только по отмене
+				atomic.AddInt32(&canceledCount, 1)
+				return nil
+			})
+		}
+
+		workersCount := 5
+		err := RunContext(ctx, tasks, Options{Workers: workersCount, MaxErrors: 1})
+
+		require.Truef(t, errors.Is(err, context.Canceled), "actual err - %v", err)
+		require.LessOrEqual(t, runTasksCount, int32(workersCount), "extra tasks were started")
+		require.Equal(t, runTasksCount, canceledCount)
+	})
+
+	t.Run("errors limit cancels running tasks", func(t *testing.T) {
+		tasksCount := 50
+		tasks := make([]TaskCtx, 0, tasksCount)
+
+		var runTasksCount, canceledCount int32
+
+		for i := 0; i < tasksCount; i++ {
+			if i == 0 {
+				tasks = append(tasks, func(context.Context) error {
+					atomic.AddInt32(&runTasksCount, 1)
+					return fmt.Errorf("error from task %d", i)
+				})
+				continue
+			}
+			tasks = append(tasks, func(ctx context.Context) error {
+				atomic.AddInt32(&runTasksCount, 1)
+				<-ctx.Done() // без отмены задача не завершится
+				atomic.AddInt32(&canceledCount, 1)
+				return nil
+			})
+		}
+
+		workersCount := 5
+		err := RunContext(context.Background(), tasks, Options{Workers: workersCount, MaxErrors: 1})
+
+		require.Truef(t, errors.Is(err, ErrErrorsLimitExceeded), "actual err - %v", err)
+		require.LessOrEqual(t, runTasksCount, int32(workersCount+1), "extra tasks were started")
+		require.Equal(t, runTasksCount-1, canceledCount)
+	})
+
+	t.Run("empty tasks", func(t *testing.T) {
+		err := RunContext(context.Background(), nil, Options{Workers: 1, MaxErrors: 1})
+
+		require.Truef(t, errors.Is(err, ErrEmptyTasks), "actual err - %v", err)
+	})
+
+	t.Run("zero workers", func(t *testing.T) {
+		var runTasksCount int32
+		tasks := []TaskCtx{
+			func(context.Context) error { atomic.AddInt32(&runTasksCount, 1); return nil },
+			func(context.Context) error { atomic.AddInt32(&runTasksCount, 1); return nil },
+		}
+
+		err := RunContext(context.Background(), tasks, Options{MaxErrors: 1})
+
+		require.NoError(t, err)
+		require.Equal(t, int32(2), runTasksCount)
+	})
+}
+
+func TestRunErrors(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("limit exceeded lists task errors", func(t *testing.T) {
+		tasksCount := 20
+		tasks := make([]Task, 0, tasksCount)
+
+		for i := 0; i < tasksCount; i++ {
+			tasks = append(tasks, func() error {
+				if i%2 == 0 {
+					return fmt.Errorf("error from task %d", i)
+				}
+				return nil
+			})
+		}
+
+		maxErrorsCount := 3
+		err := Run(tasks, 1, maxErrorsCount)
+
+		require.Truef(t, errors.Is(err, ErrErrorsLimitExceeded), "actual err - %v", err)
+
+		var runErr RunError
+		require.True(t, errors.As(err, &runErr))
+		require.True(t, runErr.LimitExceeded)
+		require.Len(t, runErr.Errors, maxErrorsCount)
+		for i, te := range runErr.Errors { // один воркер выполняет задачи по порядку
+			require.Equal(t, i*2, te.Index)
+			require.EqualError(t, te.Err, fmt.Sprintf("error from task %d", i*2))
+		}
+	})
+
+	t.Run("collect all", func(t *testing.T) {
+		tasksCount := 30
+		tasks := make([]TaskCtx, 0, tasksCount)
+		errNotFound := errors.New("not found")
+
+		var runTasksCount int32
+
+		for i := 0; i < tasksCount; i++ {
+			tasks = append(tasks, func(context.Context) error {
+				atomic.AddInt32(&runTasksCount, 1)
+				if i%3 == 0 {
+					return errNotFound
+				}
+				return nil
+			})
+		}
+
+		err := RunContext(context.Background(), tasks, Options{Workers: 5, CollectAll: true})
+
+		require.Equal(t, int32(tasksCount), runTasksCount, "not all tasks were completed")
+		require.Truef(t, errors.Is(err, errNotFound), "actual err - %v", err)
+		require.False(t, errors.Is(err, ErrErrorsLimitExceeded))
+
+		var runErr RunError
+		require.True(t, errors.As(err, &runErr))
+		require.Len(t, runErr.Errors, tasksCount/3)
+		for i, te := range runErr.Errors {
+			require.Equal(t, i*3, te.Index)
+		}
+	})
+
+	t.Run("collect all without errors", func(t *testing.T) {
+		tasks := []TaskCtx{
+			func(context.Context) error { return nil },
+		}
+
+		err := RunContext(context.Background(), tasks, Options{Workers: 5, CollectAll: true})
+
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,41 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"iter"
+)
+
+// RunChan выполняет задачи, получаемые из канала tasks, до его закрытия.
+// Ограничения на количество воркеров и ошибок те же, что у RunContext;
+// после превышения лимита ошибок или отмены ctx задачи из канала больше не читаются.
+// Номер задачи в TaskError - порядковый номер задачи в канале.
+func RunChan(ctx context.Context, tasks <-chan TaskCtx, opts Options) error {
+	return run(ctx, source{produce: func(ctx context.Context, send func(job) bool) {
+		for i := 0; ; i++ {
+			select {
+			case <-ctx.Done():
+				return
+			case task, ok := <-tasks:
+				if !ok || !send(job{idx: i, task: task}) {
+					return
+				}
+			}
+		}
+	}}, opts)
+}
+
+// RunSeq выполняет задачи, получаемые из итератора tasks.
+// Ограничения на количество воркеров и ошибок те же, что у RunContext;
+// после превышения лимита ошибок или отмены ctx итерация прекращается.
+// Номер задачи в TaskError - порядковый номер задачи в последовательности.
+func RunSeq(ctx context.Context, tasks iter.Seq[TaskCtx], opts Options) error {
+	return run(ctx, source{produce: func(_ context.Context, send func(job) bool) {
+		i := 0
+		for task := range tasks {
+			if !send(job{idx: i, task: task}) {
+				return
+			}
+			i++
+		}
+	}}, opts)
+}
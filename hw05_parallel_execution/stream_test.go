@@ -0,0 +1,161 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+// бесконечная последовательность задач, учитывающая количество запрошенных задач.
+func taskSeq(pulled *int32, task func(i int) error) func(yield func(TaskCtx) bool) {
+	return func(yield func(TaskCtx) bool) {
+		for i := 0; ; i++ {
+			atomic.AddInt32(pulled, 1)
+			if !yield(func(context.Context) error { return task(i) }) {
+				return
+			}
+		}
+	}
+}
+
+func TestRunSeq(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("finite sequence", func(t *testing.T) {
+		tasksCount := 100
+		var runTasksCount int32
+
+		seq := func(yield func(TaskCtx) bool) {
+			for i := 0; i < tasksCount; i++ {
+				if !yield(func(context.Context) error {
+					atomic.AddInt32(&runTasksCount, 1)
+					return nil
+				}) {
+					return
+				}
+			}
+		}
+
+		err := RunSeq(context.Background(), seq, Options{Workers: 5, MaxErrors: 1})
+
+		require.NoError(t, err)
+		require.Equal(t, int32(tasksCount), runTasksCount)
+	})
+
+	t.Run("stops pulling after errors limit", func(t *testing.T) {
+		var pulled, runTasksCount int32
+		seq := taskSeq(&pulled, func(i int) error {
+			atomic.AddInt32(&runTasksCount, 1)
+			return fmt.Errorf("error from task %d", i)
+		})
+
+		workersCount, maxErrorsCount := 5, 10
+		err := RunSeq(context.Background(), seq, Options{Workers: workersCount, MaxErrors: maxErrorsCount})
+
+		require.Truef(t, errors.Is(err, ErrErrorsLimitExceeded), "actual err - %v", err)
+		require.LessOrEqual(t, runTasksCount, int32(workersCount+maxErrorsCount), "extra tasks were started")
+		// кроме запущенных задач из источника могла быть взята еще одна, ожидающая свободного воркера
+		require.LessOrEqual(t, pulled, int32(workersCount+maxErrorsCount+1), "extra tasks were pulled")
+	})
+
+	t.Run("stops pulling after cancel", func(t *testing.T) {
+		var pulled int32
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		seq := taskSeq(&pulled, func(i int) error {
+			if i == 100 {
+				cancel()
+			}
+			return nil
+		})
+
+		err := RunSeq(ctx, seq, Options{Workers: 5, MaxErrors: 1})
+
+		require.Truef(t, errors.Is(err, context.Canceled), "actual err - %v", err)
+		require.Less(t, pulled, int32(200))
+	})
+}
+
+func TestRunChan(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("closed channel", func(t *testing.T) {
+		tasksCount := 100
+		var runTasksCount int32
+
+		tasks := make(chan TaskCtx)
+		go func() {
+			defer close(tasks)
+			for i := 0; i < tasksCount; i++ {
+				tasks <- func(context.Context) error {
+					atomic.AddInt32(&runTasksCount, 1)
+					return nil
+				}
+			}
+		}()
+
+		err := RunChan(context.Background(), tasks, Options{Workers: 5, MaxErrors: 1})
+
+		require.NoError(t, err)
+		require.Equal(t, int32(tasksCount), runTasksCount)
+	})
+
+	t.Run("errors limit", func(t *testing.T) {
+		var runTasksCount, sent int32
+
+		tasks := make(chan TaskCtx)
+		stop := make(chan struct{})
+		producerDone := make(chan struct{})
+		go func() {
+			defer close(producerDone)
+			for i := 0; ; i++ {
+				task := func(context.Context) error {
+					atomic.AddInt32(&runTasksCount, 1)
+					return fmt.Errorf("error from task %d", i)
+				}
+				select {
+				case <-stop:
+					return
+				case tasks <- task:
+					atomic.AddInt32(&sent, 1)
+				}
+			}
+		}()
+
+		workersCount, maxErrorsCount := 3, 5
+		err := RunChan(context.Background(), tasks, Options{Workers: workersCount, MaxErrors: maxErrorsCount})
+		close(stop)
+		<-producerDone
+
+		var runErr RunError
+		require.True(t, errors.As(err, &runErr))
+		require.True(t, runErr.LimitExceeded)
+		require.LessOrEqual(t, runTasksCount, int32(workersCount+maxErrorsCount), "extra tasks were started")
+		require.LessOrEqual(t, sent, int32(workersCount+maxErrorsCount+1), "extra tasks were read")
+	})
+
+	t.Run("empty channel", func(t *testing.T) {
+		tasks := make(chan TaskCtx)
+		close(tasks)
+
+		err := RunChan(context.Background(), tasks, Options{Workers: 5, MaxErrors: 1})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("cancel while waiting for tasks", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		tasks := make(chan TaskCtx) // задачи не поступают
+		cancel()
+
+		err := RunChan(ctx, tasks, Options{Workers: 5, MaxErrors: 1})
+
+		require.Truef(t, errors.Is(err, context.Canceled), "actual err - %v", err)
+	})
+}
@@ -0,0 +1,327 @@
+package hw06pipelineexecution
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// создать стейдж, выполняющий run в отдельной горутине.
+// После завершения run выходной канал закрывается, а оставшиеся входные значения вычитываются,
+// чтобы предыдущий стейдж не блокировался, если run прекратил чтение раньше закрытия входа.
+func newStage[I, O any](run func(ctx context.Context, in <-chan I, out chan<- O)) TypedStage[I, O] {
+	return func(ctx context.Context, in <-chan I) <-chan O {
+		out := make(chan O)
+
+		// горутина продолжает работу после закрытия out, поэтому ее завершение учитывается отдельно
+		goStage(ctx, func() {
+			defer drainChan(in)
+			defer close(out)
+
+			run(ctx, in, out)
+		})
+
+		return out
+	}
+}
+
+// отправить значение в out; false - пайплайн остановлен.
+func send[T any](ctx context.Context, out chan<- T, v T) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case out <- v:
+		return true
+	}
+}
+
+// Map создает стейдж, применяющий f к каждому значению.
+func Map[I, O any](f func(v I) O) TypedStage[I, O] {
+	return newStage(func(ctx context.Context, in <-chan I, out chan<- O) {
+		for v := range in {
+			if !send(ctx, out, f(v)) {
+				return
+			}
+		}
+	})
+}
+
+// Filter создает стейдж, пропускающий только значения, удовлетворяющие условию pred.
+func Filter[T any](pred func(v T) bool) TypedStage[T, T] {
+	return newStage(func(ctx context.Context, in <-chan T, out chan<- T) {
+		for v := range in {
+			if pred(v) && !send(ctx, out, v) {
+				return
+			}
+		}
+	})
+}
+
+// Take создает стейдж, пропускающий первые n значений.
+// После n значений выходной канал закрывается, а остальные входные значения отбрасываются.
+func Take[T any](n int) TypedStage[T, T] {
+	return newStage(func(ctx context.Context, in <-chan T, out chan<- T) {
+		for i := 0; i < n; i++ {
+			v, ok := <-in
+			if !ok || !send(ctx, out, v) {
+				return
+			}
+		}
+	})
+}
+
+// Batch создает стейдж, объединяющий значения в пачки не более size значений.
+// Неполная пачка выдается через maxWait после получения ее первого значения и при закрытии входа.
+// size <= 0 - размер не ограничен, maxWait <= 0 - время ожидания не ограничено.
+func Batch[T any](size int, maxWait time.Duration) TypedStage[T, []T] {
+	return newStage(func(ctx context.Context, in <-chan T, out chan<- []T) {
+		var batch []T
+		var timer *time.Timer
+		var timeout <-chan time.Time // nil, пока пачка пуста
+
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+
+		flush := func() bool {
+			if timer != nil {
+				timer.Stop()
+				timeout = nil
+			}
+			if len(batch) == 0 {
+				return true
+			}
+
+			b := batch
+			batch = nil
+			return send(ctx, out, b)
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+
+				batch = append(batch, v)
+				if len(batch) == 1 && maxWait > 0 {
+					timer = time.NewTimer(maxWait)
+					timeout = timer.C
+				}
+				if len(batch) == size && !flush() {
+					return
+				}
+			case <-timeout:
+				if !flush() {
+					return
+				}
+			}
+		}
+	})
+}
+
+// TumblingWindow создает стейдж, выдающий значения, полученные за очередной интервал d.
+// Интервалы не пересекаются; за интервал без значений ничего не выдается.
+// Значения последнего неполного интервала выдаются при закрытии входа. При d <= 0 вызывается panic.
+func TumblingWindow[T any](d time.Duration) TypedStage[T, []T] {
+	if d <= 0 {
+		panic(fmt.Sprintf("hw06pipelineexecution: non-positive TumblingWindow interval %v", d))
+	}
+
+	return newStage(func(ctx context.Context, in <-chan T, out chan<- []T) {
+		ticker := time.NewTicker(d)
+		defer ticker.Stop()
+
+		var window []T
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					if len(window) > 0 {
+						send(ctx, out, window)
+					}
+					return
+				}
+				window = append(window, v)
+			case <-ticker.C:
+				if len(window) == 0 {
+					continue
+				}
+				if !send(ctx, out, window) {
+					return
+				}
+				window = nil
+			}
+		}
+	})
+}
+
+// значение с моментом получения.
+type timedValue[T any] struct {
+	at time.Time
+	v  T
+}
+
+// SlidingWindow создает стейдж, каждые step выдающий значения, полученные за последние size.
+// Окна пересекаются, если step < size; пустое окно не выдается.
+// При закрытии входа выдается окно, если в нем есть еще не выданные значения.
+// При size <= 0 или step <= 0 вызывается panic.
+func SlidingWindow[T any](size, step time.Duration) TypedStage[T, []T] {
+	if size <= 0 || step <= 0 {
+		panic(fmt.Sprintf("hw06pipelineexecution: non-positive SlidingWindow size %v or step %v", size, step))
+	}
+
+	return newStage(func(ctx context.Context, in <-chan T, out chan<- []T) {
+		ticker := time.NewTicker(step)
+		defer ticker.Stop()
+
+		var window []timedValue[T]
+		fresh := false // в окне есть не выданные значения
+
+		emit := func(now time.Time) bool {
+			// отбрасываем значения, вышедшие за границу окна
+			i := 0
+			for i < len(window) && now.Sub(window[i].at) > size {
+				i++
+			}
+			window = window[i:]
+			if len(window) == 0 {
+				return true
+			}
+
+			values := make([]T, len(window))
+			for j, tv := range window {
+				values[j] = tv.v
+			}
+			fresh = false
+			return send(ctx, out, values)
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					if fresh {
+						emit(time.Now())
+					}
+					return
+				}
+				window = append(window, timedValue[T]{at: time.Now(), v: v})
+				fresh = true
+			case now := <-ticker.C:
+				if !emit(now) {
+					return
+				}
+			}
+		}
+	})
+}
+
+// Throttle создает стейдж, выдающий не более rate значений в секунду.
+// Значения не отбрасываются: стейдж ожидает, пока выдача станет допустимой. rate <= 0 - без ограничения.
+func Throttle[T any](rate float64) TypedStage[T, T] {
+	return newStage(func(ctx context.Context, in <-chan T, out chan<- T) {
+		var interval time.Duration
+		if rate > 0 {
+			interval = time.Duration(float64(time.Second) / rate)
+		}
+		var next time.Time // момент, начиная с которого допустима выдача
+
+		for v := range in {
+			if wait := time.Until(next); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+			}
+
+			if !send(ctx, out, v) {
+				return
+			}
+			next = time.Now().Add(interval)
+		}
+	})
+}
+
+// Debounce создает стейдж, выдающий значение, только если за ним в течение d не последовало другое.
+// Последнее значение выдается при закрытии входа без ожидания.
+func Debounce[T any](d time.Duration) TypedStage[T, T] {
+	return newStage(func(ctx context.Context, in <-chan T, out chan<- T) {
+		var pending T
+		var timer *time.Timer
+		var fire <-chan time.Time // nil, пока нет ожидающего значения
+
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					if fire != nil {
+						send(ctx, out, pending)
+					}
+					return
+				}
+
+				pending = v
+				if timer != nil {
+					timer.Stop()
+				}
+				timer = time.NewTimer(d)
+				fire = timer.C
+			case <-fire:
+				fire = nil
+				if !send(ctx, out, pending) {
+					return
+				}
+			}
+		}
+	})
+}
+
+// Tee создает стейдж, передающий значения дальше без изменений, и канал, в который передаются их копии.
+// Канал копий закрывается при завершении стейджа и должен читаться, иначе стейдж блокируется.
+// Стейдж можно запустить только один раз.
+func Tee[T any]() (TypedStage[T, T], <-chan T) {
+	side := make(chan T)
+
+	stage := newStage(func(ctx context.Context, in <-chan T, out chan<- T) {
+		defer close(side)
+
+		for v := range in {
+			if !send(ctx, out, v) || !send(ctx, side, v) {
+				return
+			}
+		}
+	})
+
+	return stage, side
+}
+
+// AsStage преобразует типизированный стейдж в Stage для ExecutePipeline.
+// Входные значения должны иметь тип I. Стейдж получает собственный контекст, не связанный с пайплайном,
+// и завершается при закрытии входного канала, которое ExecutePipeline выполняет и при закрытии done.
+// Ошибки стейджей TryMap и Parallel внутри stage отбрасываются вместе со значениями (SkipErrors):
+// Stage не может их вернуть. Для обработки ошибок используйте StartPipeline или TryStage.
+func AsStage[I, O any](stage TypedStage[I, O]) Stage {
+	return untypedStage(nil, SkipErrors, stage)
+}
@@ -0,0 +1,241 @@
+package hw06pipelineexecution
+
+import (
+	"context"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// источник значений data, отправляемых с паузой pause.
+func timedSource[T any](pause time.Duration, data ...T) <-chan T {
+	in := make(chan T)
+	go func() {
+		defer close(in)
+		for _, v := range data {
+			time.Sleep(pause)
+			in <- v
+		}
+	}()
+	return in
+}
+
+func collectAll[T any](ch <-chan T) []T {
+	res := make([]T, 0)
+	for v := range ch {
+		res = append(res, v)
+	}
+	return res
+}
+
+// проверить, что количество горутин вернулось к исходному.
+// require.Eventually не подходит: проверка условия сама выполняется в отдельной горутине.
+func requireNoLeaks(t *testing.T, before int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	require.LessOrEqual(t, runtime.NumGoroutine(), before, "goroutines leaked")
+}
+
+func TestCombinators(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("map filter take", func(t *testing.T) {
+		stage := Chain(
+			Stages(Filter(func(v int) bool { return v%2 == 0 }), Take[int](3)),
+			Map(strconv.Itoa),
+		)
+
+		res := collectAll(ExecuteTypedPipeline(ctx, timedSource(0, 1, 2, 3, 4, 5, 6, 7, 8), stage))
+		require.Equal(t, []string{"2", "4", "6"}, res)
+	})
+
+	t.Run("take drains input", func(t *testing.T) {
+		before := runtime.NumGoroutine()
+
+		in := make(chan int, 5)
+		for i := 1; i <= 5; i++ {
+			in <- i
+		}
+		close(in)
+
+		res := collectAll(ExecuteTypedPipeline(ctx, in, Take[int](1)))
+		require.Equal(t, []int{1}, res)
+		requireNoLeaks(t, before)
+	})
+
+	t.Run("batch by size", func(t *testing.T) {
+		res := collectAll(ExecuteTypedPipeline(ctx, timedSource(0, 1, 2, 3, 4, 5), Batch[int](2, time.Hour)))
+		require.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, res)
+	})
+
+	t.Run("batch by time", func(t *testing.T) {
+		in := make(chan int)
+		out := ExecuteTypedPipeline(ctx, in, Batch[int](10, 20*time.Millisecond))
+
+		in <- 1
+		in <- 2
+		require.Equal(t, []int{1, 2}, <-out) // пачка выдана по времени, не дожидаясь размера
+		in <- 3
+		close(in)
+		require.Equal(t, [][]int{{3}}, collectAll(out))
+	})
+
+	t.Run("tumbling window", func(t *testing.T) {
+		in := make(chan int)
+		out := ExecuteTypedPipeline(ctx, in, TumblingWindow[int](30*time.Millisecond))
+
+		in <- 1
+		in <- 2
+		require.Equal(t, []int{1, 2}, <-out)
+		in <- 3
+		require.Equal(t, []int{3}, <-out) // окна не пересекаются
+		in <- 4
+		close(in)
+		require.Equal(t, [][]int{{4}}, collectAll(out))
+	})
+
+	t.Run("sliding window", func(t *testing.T) {
+		in := make(chan int)
+		out := ExecuteTypedPipeline(ctx, in, SlidingWindow[int](time.Hour, 20*time.Millisecond))
+
+		in <- 1
+		require.Equal(t, []int{1}, <-out)
+		in <- 2
+		require.Equal(t, []int{1, 2}, <-out) // окна пересекаются
+		close(in)
+		collectAll(out)
+	})
+
+	t.Run("sliding window drops old values", func(t *testing.T) {
+		in := make(chan int)
+		out := ExecuteTypedPipeline(ctx, in, SlidingWindow[int](30*time.Millisecond, 10*time.Millisecond))
+
+		go func() {
+			defer close(in)
+			in <- 1
+			time.Sleep(60 * time.Millisecond)
+			in <- 2
+		}()
+
+		windows := collectAll(out)
+		require.Equal(t, []int{1}, windows[0])
+		require.Equal(t, []int{2}, windows[len(windows)-1])
+		for _, w := range windows {
+			require.Len(t, w, 1) // значения не попадают в одно окно
+		}
+	})
+
+	t.Run("invalid windows", func(t *testing.T) {
+		require.Panics(t, func() { TumblingWindow[int](0) })
+		require.Panics(t, func() { SlidingWindow[int](0, time.Second) })
+		require.Panics(t, func() { SlidingWindow[int](time.Second, -time.Second) })
+	})
+
+	t.Run("as stage skips errors", func(t *testing.T) {
+		even := TryMap(func(_ context.Context, v int) (int, error) {
+			if v%2 != 0 {
+				return 0, errOdd
+			}
+			return v, nil
+		})
+
+		in := make(Bi, 5)
+		for i := 0; i < 5; i++ {
+			in <- i
+		}
+		close(in)
+
+		require.Equal(t, []interface{}{0, 2, 4}, collectAll(ExecutePipeline(in, nil, AsStage(even))))
+	})
+
+	t.Run("throttle", func(t *testing.T) {
+		start := time.Now()
+		res := collectAll(ExecuteTypedPipeline(ctx, timedSource(0, 1, 2, 3, 4, 5), Throttle[int](100)))
+
+		require.Equal(t, []int{1, 2, 3, 4, 5}, res)
+		require.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	})
+
+	t.Run("debounce", func(t *testing.T) {
+		in := make(chan int)
+		out := ExecuteTypedPipeline(ctx, in, Debounce[int](20*time.Millisecond))
+
+		in <- 1
+		in <- 2
+		in <- 3
+		require.Equal(t, 3, <-out) // серия значений схлопнута до последнего
+		in <- 4
+		close(in)
+		require.Equal(t, []int{4}, collectAll(out))
+	})
+
+	t.Run("tee", func(t *testing.T) {
+		tee, side := Tee[int]()
+
+		var copies []int
+		wg := sync.WaitGroup{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			copies = collectAll(side)
+		}()
+
+		res := collectAll(ExecuteTypedPipeline(ctx, timedSource(0, 1, 2, 3), tee))
+		wg.Wait()
+
+		require.Equal(t, []int{1, 2, 3}, res)
+		require.Equal(t, []int{1, 2, 3}, copies)
+	})
+}
+
+func TestCombinatorsDone(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	in := make(Bi)
+	done := make(Bi)
+	go func() {
+		defer close(in)
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			case in <- i:
+			}
+		}
+	}()
+
+	tee, side := Tee[int]()
+	go drainChan(side)
+
+	stages := []Stage{
+		AsStage(Map(func(v int) int { return v + 1 })),
+		AsStage(Filter(func(v int) bool { return v%2 == 0 })),
+		AsStage(tee),
+		AsStage(Throttle[int](1000)),
+		AsStage(Debounce[int](time.Millisecond)),
+		AsStage(SlidingWindow[int](time.Second, time.Millisecond)),
+		AsStage(Map(func(w []int) int { return len(w) })),
+		AsStage(TumblingWindow[int](time.Millisecond)),
+		AsStage(Batch[[]int](3, time.Millisecond)),
+		AsStage(Take[[][]int](1000)),
+	}
+
+	go func() {
+		<-time.After(50 * time.Millisecond)
+		close(done)
+	}()
+
+	for v := range ExecutePipeline(in, done, stages...) {
+		require.IsType(t, [][]int{}, v)
+	}
+
+	requireNoLeaks(t, before)
+}
@@ -0,0 +1,58 @@
+package hw06pipelineexecution
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StageError - ошибка обработки значения стейджем.
+type StageError struct {
+	Stage int         // номер стейджа в пайплайне, начиная с 0
+	Item  interface{} // значение, при обработке которого произошла ошибка
+	Err   error       // ошибка, которую вернул стейдж
+}
+
+func (e StageError) Error() string {
+	return fmt.Sprintf("stage %d: item %v: %v", e.Stage, e.Item, e.Err)
+}
+
+func (e StageError) Unwrap() error {
+	return e.Err
+}
+
+// ItemError - ошибка обработки значения Item, которую Stage отправляет в выходной канал вместо результата.
+// Пайплайн не передает ее следующему стейджу, а обрабатывает как StageError с номером стейджа
+// согласно политике, заданной WithErrorPolicy.
+type ItemError struct {
+	Item interface{} // значение, при обработке которого произошла ошибка
+	Err  error       // ошибка обработки
+}
+
+func (e ItemError) Error() string {
+	return fmt.Sprintf("item %v: %v", e.Item, e.Err)
+}
+
+func (e ItemError) Unwrap() error {
+	return e.Err
+}
+
+// StageErrors - ошибки стейджей в порядке их возникновения.
+type StageErrors []StageError
+
+func (e StageErrors) Error() string {
+	s := make([]string, len(e))
+	for i, se := range e {
+		s[i] = se.Error()
+	}
+
+	return strings.Join(s, "; ")
+}
+
+func (e StageErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, se := range e {
+		errs[i] = se
+	}
+
+	return errs
+}
@@ -0,0 +1,188 @@
+package hw06pipelineexecution
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errOdd = errors.New("odd value")
+
+func TestStageErrors(t *testing.T) {
+	err := error(StageErrors{
+		{Stage: 1, Item: 3, Err: errOdd},
+		{Stage: 2, Item: "x", Err: io.EOF},
+	})
+
+	require.Equal(t, "stage 1: item 3: odd value; stage 2: item x: EOF", err.Error())
+	require.True(t, errors.Is(err, errOdd))
+	require.True(t, errors.Is(err, io.EOF))
+
+	var se StageError
+	require.True(t, errors.As(err, &se))
+	require.Equal(t, 1, se.Stage)
+	require.Equal(t, 3, se.Item)
+}
+
+func TestErrorPolicy(t *testing.T) {
+	inc := TryMap(func(_ context.Context, v int) (int, error) { return v + 1, nil })
+	// отбрасывает нечетные значения с ошибкой
+	even := func(processed *int32) TypedStage[int, int] {
+		return TryMap(func(_ context.Context, v int) (int, error) {
+			atomic.AddInt32(processed, 1)
+			if v%2 != 0 {
+				return 0, errOdd
+			}
+			return v, nil
+		})
+	}
+	source := func(n int) <-chan int {
+		in := make(chan int)
+		go func() {
+			defer close(in)
+			for i := 0; i < n; i++ {
+				in <- i
+			}
+		}()
+		return in
+	}
+	collect := func(p *Pipeline[int]) []int {
+		res := make([]int, 0)
+		for v := range p.Out() {
+			res = append(res, v)
+		}
+		return res
+	}
+
+	t.Run("fail fast", func(t *testing.T) {
+		var processed int32
+		p := StartPipeline(context.Background(), source(100), Chain(inc, even(&processed)))
+
+		res := collect(p)
+		require.Empty(t, res) // первое значение после inc нечетное
+
+		var se StageError
+		require.True(t, errors.As(p.Err(), &se))
+		require.Equal(t, StageError{Stage: 1, Item: 1, Err: errOdd}, se)
+		require.Less(t, atomic.LoadInt32(&processed), int32(100), "pipeline was not stopped")
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		var processed int32
+		p := StartPipeline(context.Background(), source(6), Stages(inc, even(&processed), inc),
+			WithErrorPolicy(SkipErrors))
+
+		require.Equal(t, []int{3, 5, 7}, collect(p))
+		require.NoError(t, p.Err())
+		require.Equal(t, int32(6), processed)
+	})
+
+	t.Run("collect", func(t *testing.T) {
+		var processed int32
+		p := StartPipeline(context.Background(), source(4), Stages(inc, inc, even(&processed)),
+			WithErrorPolicy(CollectErrors))
+
+		require.Equal(t, []int{2, 4}, collect(p))
+
+		var errs StageErrors
+		require.True(t, errors.As(p.Err(), &errs))
+		require.Equal(t, StageErrors{
+			{Stage: 2, Item: 3, Err: errOdd},
+			{Stage: 2, Item: 5, Err: errOdd},
+		}, errs)
+	})
+
+	t.Run("nested chains keep numbering", func(t *testing.T) {
+		var processed int32
+		stage := Chain(Chain(inc, inc), Chain(inc, even(&processed)))
+		p := StartPipeline(context.Background(), source(2), stage)
+
+		collect(p)
+
+		var se StageError
+		require.True(t, errors.As(p.Err(), &se))
+		require.Equal(t, 3, se.Stage)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		p := StartPipeline(ctx, make(chan int), inc)
+		collect(p)
+
+		require.ErrorIs(t, p.Err(), context.Canceled)
+	})
+}
+
+func TestStageItemErrors(t *testing.T) {
+	inc := TryStage(func(v interface{}) (interface{}, error) { return v.(int) + 1, nil })
+	even := TryStage(func(v interface{}) (interface{}, error) {
+		if v.(int)%2 != 0 {
+			return nil, errOdd
+		}
+		return v, nil
+	})
+	source := func(n int) In {
+		in := make(Bi, n)
+		for i := 0; i < n; i++ {
+			in <- i
+		}
+		close(in)
+		return in
+	}
+
+	t.Run("collect", func(t *testing.T) {
+		p := RunPipelineWith(source(4), nil, []Option{WithErrorPolicy(CollectErrors)}, inc, even)
+
+		require.Equal(t, []interface{}{2, 4}, collectAll(p.Out()))
+
+		var errs StageErrors
+		require.True(t, errors.As(p.Wait(), &errs))
+		require.Equal(t, StageErrors{
+			{Stage: 1, Item: 1, Err: errOdd},
+			{Stage: 1, Item: 3, Err: errOdd},
+		}, errs)
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		p := RunPipelineWith(source(4), nil, []Option{WithErrorPolicy(SkipErrors)}, even, inc)
+
+		require.Equal(t, []interface{}{1, 3}, collectAll(p.Out()))
+		require.NoError(t, p.Wait())
+	})
+
+	t.Run("fail fast", func(t *testing.T) {
+		// стейдж, написанный вручную, сообщает об ошибке через ItemError
+		failAt := func(in In) Out {
+			out := make(Bi)
+			go func() {
+				defer close(out)
+				for v := range in {
+					if v == 3 {
+						out <- ItemError{Item: v, Err: io.EOF}
+						continue
+					}
+					out <- v
+				}
+			}()
+			return out
+		}
+
+		p := RunPipeline(source(100), nil, inc, inc, failAt)
+		collectAll(p.Out())
+
+		var se StageError
+		require.True(t, errors.As(p.Wait(), &se))
+		require.Equal(t, StageError{Stage: 2, Item: 3, Err: io.EOF}, se)
+	})
+
+	t.Run("execute pipeline stops", func(t *testing.T) {
+		res := collectAll(ExecutePipeline(source(10), nil, even))
+		require.LessOrEqual(t, len(res), 1) // первая ошибка на значении 1 останавливает пайплайн
+	})
+}
@@ -2,7 +2,10 @@ module github.com/DimVlas/otus_hw/hw06_pipeline_execution
 
 go 1.22
 
-require github.com/stretchr/testify v1.7.0
+require (
+	github.com/stretchr/testify v1.7.0
+	go.uber.org/goleak v1.1.10
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
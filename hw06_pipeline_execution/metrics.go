@@ -0,0 +1,82 @@
+package hw06pipelineexecution
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// StageMetrics - метрики стейджа пайплайна.
+// Время ожидания измеряется на границах стейджа: большой RecvWait означает, что стейдж простаивает
+// из-за предыдущих, большой SendWait - что его сдерживают следующие.
+// Узкое место - стейдж с заполненным входным буфером, у предыдущего стейджа велик SendWait,
+// а у следующего - RecvWait.
+type StageMetrics struct {
+	In         uint64        // значений передано во вход стейджа
+	Out        uint64        // значений получено с выхода стейджа
+	RecvWait   time.Duration // время ожидания значений для входа стейджа
+	SendWait   time.Duration // время, в течение которого выход стейджа не принимался следующим
+	QueueDepth int           // текущее количество значений во входном буфере стейджа
+	QueueCap   int           // размер входного буфера стейджа
+}
+
+// граница между стейджами: учет значений, прошедших через wrap. Методы допускают вызов для nil.
+type boundary struct {
+	recvCount atomic.Uint64
+	sendCount atomic.Uint64
+	recvWait  atomic.Int64 // время ожидания значения от предыдущего стейджа, нс
+	sendWait  atomic.Int64 // время ожидания приема значения следующим стейджем, нс
+
+	depth    func() int // текущее количество значений в буфере
+	capacity int
+}
+
+// задать источник текущего заполнения буфера границы.
+// Для границы, общей для копий стейджа, учитывается буфер первой копии.
+func (b *boundary) watch(depth func() int, capacity int) {
+	if b == nil || b.depth != nil {
+		return
+	}
+
+	b.depth = depth
+	b.capacity = capacity
+}
+
+func (b *boundary) received(wait time.Duration) {
+	if b == nil {
+		return
+	}
+
+	b.recvCount.Add(1)
+	b.recvWait.Add(int64(wait))
+}
+
+func (b *boundary) sent(wait time.Duration) {
+	if b == nil {
+		return
+	}
+
+	b.sendCount.Add(1)
+	b.sendWait.Add(int64(wait))
+}
+
+// Metrics возвращает текущие метрики стейджей в порядке их следования.
+func (p *Pipeline[O]) Metrics() []StageMetrics {
+	p.state.mtx.Lock()
+	bs := p.state.boundaries
+	p.state.mtx.Unlock()
+
+	metrics := make([]StageMetrics, len(bs)-1)
+	for i := range metrics {
+		in, out := bs[i], bs[i+1]
+		metrics[i] = StageMetrics{
+			In:         in.sendCount.Load(),
+			Out:        out.recvCount.Load(),
+			RecvWait:   time.Duration(in.recvWait.Load()),
+			SendWait:   time.Duration(out.sendWait.Load()),
+			QueueDepth: in.depth(),
+			QueueCap:   in.capacity,
+		}
+	}
+
+	return metrics
+}
@@ -0,0 +1,127 @@
+package hw06pipelineexecution
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipelineBuffers(t *testing.T) {
+	inc := Map(func(v int) int { return v + 1 })
+
+	t.Run("capacity", func(t *testing.T) {
+		in := make(chan int)
+		close(in)
+		p := StartPipeline(context.Background(), in, Stages(inc, inc, inc),
+			WithBuffer(4), WithStageBuffer(1, 8), WithStageBuffer(3, 1))
+		defer drainChan(p.Out())
+
+		m := p.Metrics()
+		require.Len(t, m, 3)
+		require.Equal(t, 4, m[0].QueueCap)
+		require.Equal(t, 8, m[1].QueueCap)
+		require.Equal(t, 4, m[2].QueueCap)
+		require.Equal(t, 1, cap(p.Out()))
+	})
+
+	t.Run("untyped stages", func(t *testing.T) {
+		double := func(in In) Out {
+			out := make(Bi)
+			go func() {
+				defer close(out)
+				for v := range in {
+					out <- v.(int) * 2
+				}
+			}()
+			return out
+		}
+
+		in := make(Bi, 3)
+		for i := 1; i <= 3; i++ {
+			in <- i
+		}
+		close(in)
+
+		p := RunPipelineWith(in, nil, []Option{WithBuffer(4), WithStageBuffer(1, 8)}, double, double)
+
+		m := p.Metrics()
+		require.Len(t, m, 2)
+		require.Equal(t, 4, m[0].QueueCap)
+		require.Equal(t, 8, m[1].QueueCap)
+		require.Equal(t, 4, cap(p.Out()))
+		require.Equal(t, []interface{}{4, 8, 12}, collectAll(p.Out()))
+		require.NoError(t, p.Wait())
+	})
+
+	t.Run("queue depth", func(t *testing.T) {
+		in := make(chan int)
+		release := make(chan struct{})
+		blocked := func(_ context.Context, in <-chan int) <-chan int {
+			out := make(chan int)
+			go func() {
+				defer close(out)
+				<-release // стейдж не читает вход, пока его не отпустят
+				for v := range in {
+					out <- v
+				}
+			}()
+			return out
+		}
+
+		p := StartPipeline(context.Background(), in, Chain(inc, blocked), WithBuffer(3))
+		go func() {
+			defer close(in)
+			for i := 0; i < 10; i++ {
+				in <- i
+			}
+		}()
+
+		require.Eventually(t, func() bool {
+			return p.Metrics()[1].QueueDepth == 3
+		}, time.Second, time.Millisecond)
+
+		close(release)
+		require.Len(t, collectAll(p.Out()), 10)
+		require.Zero(t, p.Metrics()[1].QueueDepth)
+	})
+}
+
+func TestPipelineMetrics(t *testing.T) {
+	t.Run("counts", func(t *testing.T) {
+		even := Filter(func(v int) bool { return v%2 == 0 })
+		half := Map(func(v int) int { return v / 2 })
+		p := StartPipeline(context.Background(), timedSource(0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10), Stages(even, half))
+
+		require.Equal(t, []int{1, 2, 3, 4, 5}, collectAll(p.Out()))
+
+		m := p.Metrics()
+		require.Equal(t, StageMetrics{In: 10, Out: 5}, StageMetrics{In: m[0].In, Out: m[0].Out})
+		require.Equal(t, StageMetrics{In: 5, Out: 5}, StageMetrics{In: m[1].In, Out: m[1].Out})
+	})
+
+	t.Run("bottleneck", func(t *testing.T) {
+		fast := Map(func(v int) int { return v })
+		slow := Map(func(v int) int {
+			time.Sleep(5 * time.Millisecond)
+			return v
+		})
+
+		in := make(chan int, 20)
+		for i := 0; i < 20; i++ {
+			in <- i
+		}
+		close(in)
+
+		p := StartPipeline(context.Background(), in, Stages(fast, slow, fast))
+		require.Len(t, collectAll(p.Out()), 20)
+
+		m := p.Metrics()
+		// предыдущий стейдж ждет медленный, следующий простаивает
+		require.Greater(t, m[0].SendWait, 50*time.Millisecond)
+		require.Greater(t, m[2].RecvWait, 50*time.Millisecond)
+		require.Less(t, m[1].SendWait, m[0].SendWait)
+		require.Less(t, m[0].RecvWait, m[2].RecvWait)
+	})
+}
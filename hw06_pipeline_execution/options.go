@@ -0,0 +1,53 @@
+package hw06pipelineexecution
+
+// ErrorPolicy - реакция пайплайна на ошибку обработки значения стейджем.
+type ErrorPolicy int
+
+const (
+	FailFast      ErrorPolicy = iota // остановить пайплайн на первой ошибке и вернуть ее
+	SkipErrors                       // отбросить значение и продолжить, ошибки не возвращаются
+	CollectErrors                    // отбросить значение и продолжить, вернуть все ошибки
+)
+
+// Option - параметр запуска пайплайна.
+type Option func(*config)
+
+type config struct {
+	errorPolicy  ErrorPolicy // реакция на ошибку стейджа
+	buffer       int         // размер буфера каналов между стейджами
+	stageBuffers map[int]int // размер буфера входного канала стейджа по его номеру
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{errorPolicy: FailFast}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// WithErrorPolicy задает реакцию пайплайна на ошибку стейджа, по умолчанию FailFast.
+func WithErrorPolicy(policy ErrorPolicy) Option {
+	return func(c *config) {
+		c.errorPolicy = policy
+	}
+}
+
+// WithBuffer задает размер буфера каналов между стейджами, по умолчанию каналы не буферизованы.
+func WithBuffer(size int) Option {
+	return func(c *config) {
+		c.buffer = size
+	}
+}
+
+// WithStageBuffer задает размер буфера входного канала стейджа с номером stage, начиная с 0.
+// Номер, равный количеству стейджей, задает буфер выходного канала пайплайна.
+func WithStageBuffer(stage, size int) Option {
+	return func(c *config) {
+		if c.stageBuffers == nil {
+			c.stageBuffers = make(map[int]int)
+		}
+		c.stageBuffers[stage] = size
+	}
+}
@@ -0,0 +1,191 @@
+package hw06pipelineexecution
+
+import (
+	"context"
+	"sync"
+)
+
+// FanInMode - порядок объединения результатов параллельного стейджа.
+type FanInMode int
+
+const (
+	Ordered   FanInMode = iota // результаты в порядке входных значений
+	Unordered                  // результаты по мере готовности
+)
+
+// значение с порядковым номером.
+type seqItem[T any] struct {
+	seq  int
+	v    T
+	skip bool // значение отброшено из-за ошибки
+}
+
+// Parallel создает стейдж, применяющий f к значениям в workers горутинах.
+// В режиме Ordered результаты выдаются в порядке входных значений: готовые раньше очереди
+// ожидают в буфере упорядочивания, поэтому одновременно обрабатывается и ожидает не более workers значений.
+// Ошибки f обрабатываются так же, как в TryMap.
+func Parallel[I, O any](workers int, mode FanInMode, f func(ctx context.Context, v I) (O, error)) TypedStage[I, O] {
+	if workers < 1 {
+		workers = 1
+	}
+
+	return func(ctx context.Context, in <-chan I) <-chan O {
+		idx := stageIndex(ctx)
+
+		var window chan struct{} // места в буфере упорядочивания
+		if mode == Ordered {
+			window = make(chan struct{}, workers)
+		}
+
+		// нумеруем входные значения
+		items := make(chan seqItem[I])
+		goStage(ctx, func() {
+			defer close(items)
+
+			seq := 0
+			for v := range in {
+				if window != nil {
+					window <- struct{}{}
+				}
+				items <- seqItem[I]{seq: seq, v: v}
+				seq++
+			}
+		})
+
+		results := make(chan seqItem[O])
+		wg := sync.WaitGroup{}
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			goStage(ctx, func() {
+				defer wg.Done()
+
+				for it := range items {
+					if ctx.Err() != nil { // пайплайн остановлен, оставшиеся значения не обрабатываются
+						results <- seqItem[O]{seq: it.seq, skip: true}
+						continue
+					}
+
+					res, err := f(ctx, it.v)
+					if err != nil {
+						reportError(ctx, StageError{Stage: idx, Item: it.v, Err: err})
+					}
+					results <- seqItem[O]{seq: it.seq, v: res, skip: err != nil}
+				}
+			})
+		}
+
+		goStage(ctx, func() {
+			wg.Wait()
+			close(results)
+		})
+
+		out := make(chan O)
+		goStage(ctx, func() {
+			defer close(out)
+
+			if mode == Ordered {
+				reorder(results, out, window)
+				return
+			}
+			for r := range results {
+				if !r.skip {
+					out <- r.v
+				}
+			}
+		})
+
+		return out
+	}
+}
+
+// передать результаты в out в порядке номеров, освобождая место в window после выдачи каждого.
+func reorder[T any](results <-chan seqItem[T], out chan<- T, window <-chan struct{}) {
+	pending := make(map[int]seqItem[T])
+	next := 0
+
+	for r := range results {
+		pending[r.seq] = r
+
+		for {
+			p, ok := pending[next]
+			if !ok {
+				break
+			}
+
+			delete(pending, next)
+			next++
+			if !p.skip {
+				out <- p.v
+			}
+			<-window
+		}
+	}
+}
+
+// FanOut запускает workers копий стейджа stage, читающих общий входной канал,
+// и объединяет их выходы в один по мере готовности значений.
+// Стейдж может быть составным (Chain, Stages): копии получают те же номера стейджей,
+// а метрики их границ суммируются.
+func FanOut[I, O any](workers int, stage TypedStage[I, O]) TypedStage[I, O] {
+	if workers < 1 {
+		workers = 1
+	}
+
+	return func(ctx context.Context, in <-chan I) <-chan O {
+		state := stateFrom(ctx)
+		m := state.mark()
+
+		outs := make([]<-chan O, workers)
+		outs[0] = stage(ctx, in)
+		state.replicate(m, workers, func(i int) {
+			outs[i] = stage(ctx, in)
+		})
+
+		return merge(ctx, outs...)
+	}
+}
+
+// ParallelStage создает стейдж для ExecutePipeline, применяющий f к значениям в workers горутинах.
+// Порядок результатов определяется mode, как в Parallel. После закрытия done, переданного
+// и в ExecutePipeline, значения, ожидающие обработки, отбрасываются; обработка текущих завершается.
+func ParallelStage(done In, workers int, mode FanInMode, f func(v interface{}) interface{}) Stage {
+	stage := Parallel(workers, mode, func(_ context.Context, v interface{}) (interface{}, error) {
+		return f(v), nil
+	})
+
+	return untypedStage(done, SkipErrors, stage)
+}
+
+// FanOutStage запускает workers копий стейджа stage для ExecutePipeline, как FanOut.
+// После закрытия done, переданного и в ExecutePipeline, результаты копий отбрасываются.
+func FanOutStage(done In, workers int, stage Stage) Stage {
+	fanOut := FanOut(workers, func(_ context.Context, in <-chan interface{}) <-chan interface{} {
+		return stage(in)
+	})
+
+	return untypedStage(done, SkipErrors, fanOut)
+}
+
+// объединить значения каналов в один, выходной канал закрывается после закрытия всех входных.
+func merge[T any](ctx context.Context, ins ...<-chan T) <-chan T {
+	out := make(chan T)
+	wg := sync.WaitGroup{}
+
+	for _, in := range ins {
+		wg.Add(1)
+		goStage(ctx, func() {
+			defer wg.Done()
+
+			for v := range in {
+				out <- v
+			}
+		})
+	}
+
+	goStage(ctx, func() {
+		wg.Wait()
+		close(out)
+	})
+
+	return out
+}
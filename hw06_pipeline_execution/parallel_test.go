@@ -0,0 +1,255 @@
+package hw06pipelineexecution
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallel(t *testing.T) {
+	source := func(n int) <-chan int {
+		in := make(chan int)
+		go func() {
+			defer close(in)
+			for i := 0; i < n; i++ {
+				in <- i
+			}
+		}()
+		return in
+	}
+	// обработка тем дольше, чем меньше значение: более поздние значения готовы раньше
+	slowHead := func(n int) func(context.Context, int) (int, error) {
+		return func(_ context.Context, v int) (int, error) {
+			time.Sleep(time.Duration(n-v) * time.Millisecond)
+			return v * 10, nil
+		}
+	}
+
+	t.Run("ordered", func(t *testing.T) {
+		const n = 20
+		res := make([]int, 0, n)
+		for v := range ExecuteTypedPipeline(context.Background(), source(n), Parallel(4, Ordered, slowHead(n))) {
+			res = append(res, v)
+		}
+
+		exp := make([]int, n)
+		for i := range exp {
+			exp[i] = i * 10
+		}
+		require.Equal(t, exp, res)
+	})
+
+	t.Run("unordered", func(t *testing.T) {
+		const n = 20
+		res := make([]int, 0, n)
+		for v := range ExecuteTypedPipeline(context.Background(), source(n), Parallel(4, Unordered, slowHead(n))) {
+			res = append(res, v)
+		}
+
+		require.Len(t, res, n)
+		require.False(t, sort.IntsAreSorted(res), "values were not reordered by readiness")
+		sort.Ints(res)
+		for i, v := range res {
+			require.Equal(t, i*10, v)
+		}
+	})
+
+	t.Run("workers run concurrently", func(t *testing.T) {
+		var running, peak int32
+		stage := Parallel(5, Ordered, func(_ context.Context, v int) (int, error) {
+			cur := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if cur <= p || atomic.CompareAndSwapInt32(&peak, p, cur) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return v, nil
+		})
+
+		drainChan(ExecuteTypedPipeline(context.Background(), source(20), stage))
+		require.Equal(t, int32(5), peak)
+	})
+
+	t.Run("ordered with errors", func(t *testing.T) {
+		stage := Parallel(3, Ordered, func(_ context.Context, v int) (int, error) {
+			if v%3 == 0 {
+				return 0, errOdd
+			}
+			return v, nil
+		})
+
+		p := StartPipeline(context.Background(), source(10), stage, WithErrorPolicy(CollectErrors))
+		res := make([]int, 0)
+		for v := range p.Out() {
+			res = append(res, v)
+		}
+
+		require.Equal(t, []int{1, 2, 4, 5, 7, 8}, res)
+
+		var errs StageErrors
+		require.True(t, errors.As(p.Err(), &errs))
+		require.Len(t, errs, 4)
+	})
+
+	t.Run("fan out", func(t *testing.T) {
+		var instances int32
+		double := func(_ context.Context, in <-chan int) <-chan int {
+			atomic.AddInt32(&instances, 1)
+			out := make(chan int)
+			go func() {
+				defer close(out)
+				for v := range in {
+					out <- v * 2
+				}
+			}()
+			return out
+		}
+
+		res := make([]int, 0, 10)
+		for v := range ExecuteTypedPipeline(context.Background(), source(10), FanOut(3, double)) {
+			res = append(res, v)
+		}
+
+		sort.Ints(res)
+		require.Equal(t, []int{0, 2, 4, 6, 8, 10, 12, 14, 16, 18}, res)
+		require.Equal(t, int32(3), instances)
+	})
+
+	t.Run("fan out composite stage", func(t *testing.T) {
+		inc := TryMap(func(_ context.Context, v int) (int, error) { return v + 1, nil })
+		even := TryMap(func(_ context.Context, v int) (int, error) {
+			if v%2 != 0 {
+				return 0, errOdd
+			}
+			return v, nil
+		})
+
+		p := StartPipeline(context.Background(), source(10), Chain(inc, FanOut(3, Chain(inc, even))),
+			WithErrorPolicy(CollectErrors))
+		res := collectAll(p.Out())
+		sort.Ints(res)
+		require.Equal(t, []int{2, 4, 6, 8, 10}, res)
+
+		var errs StageErrors
+		require.True(t, errors.As(p.Wait(), &errs))
+		require.Len(t, errs, 5)
+		for _, se := range errs {
+			require.Equal(t, 2, se.Stage) // копии получают номер стейджа первой копии
+		}
+
+		m := p.Metrics()
+		require.Len(t, m, 3)
+		require.Equal(t, StageMetrics{In: 10, Out: 10}, StageMetrics{In: m[1].In, Out: m[1].Out})
+		require.Equal(t, StageMetrics{In: 10, Out: 5}, StageMetrics{In: m[2].In, Out: m[2].Out})
+	})
+}
+
+func TestParallelStage(t *testing.T) {
+	wg := sync.WaitGroup{}
+	slow := func(v interface{}) interface{} {
+		time.Sleep(sleepPerStage)
+		return v.(int) * 2
+	}
+	// стейдж с отслеживанием завершения горутины
+	g := func(f func(v interface{}) interface{}) Stage {
+		return func(in In) Out {
+			out := make(Bi)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer close(out)
+				for v := range in {
+					out <- f(v)
+				}
+			}()
+			return out
+		}
+	}
+
+	t.Run("ordered speeds up slow stage", func(t *testing.T) {
+		in := make(Bi)
+		go func() {
+			defer close(in)
+			for i := 1; i <= 8; i++ {
+				in <- i
+			}
+		}()
+
+		start := time.Now()
+		res := make([]int, 0, 8)
+		for v := range ExecutePipeline(in, nil, ParallelStage(nil, 8, Ordered, slow), g(func(v interface{}) interface{} {
+			return v.(int) + 1
+		})) {
+			res = append(res, v.(int))
+		}
+
+		require.Equal(t, []int{3, 5, 7, 9, 11, 13, 15, 17}, res)
+		require.Less(t, int64(time.Since(start)), int64(2*sleepPerStage))
+		wg.Wait()
+	})
+
+	t.Run("done", func(t *testing.T) {
+		in := make(Bi)
+		done := make(Bi)
+		go func() {
+			defer close(in)
+			for i := 0; i < 100; i++ {
+				select {
+				case <-done:
+					return
+				case in <- i:
+				}
+			}
+		}()
+		go func() {
+			<-time.After(sleepPerStage / 2)
+			close(done)
+		}()
+
+		identity := func(v interface{}) interface{} { return v }
+		res := make([]interface{}, 0)
+		for v := range ExecutePipeline(in, done, FanOutStage(done, 3, g(slow)), ParallelStage(done, 2, Unordered, identity)) {
+			res = append(res, v)
+		}
+
+		wg.Wait() // все горутины стейджей завершились
+		require.Empty(t, res)
+	})
+
+	t.Run("done while worker is busy", func(t *testing.T) {
+		in := make(Bi, 100)
+		for i := 0; i < 100; i++ {
+			in <- i
+		}
+		close(in)
+		done := make(Bi)
+
+		var calls int32
+		started := make(chan struct{}, 100)
+		busy := func(v interface{}) interface{} {
+			atomic.AddInt32(&calls, 1)
+			started <- struct{}{}
+			time.Sleep(sleepPerStage / 2)
+			return v
+		}
+
+		p := RunPipeline(in, done, ParallelStage(done, 2, Unordered, busy))
+		<-started
+		<-started
+		time.Sleep(sleepPerStage / 10) // следующее значение ожидает свободного воркера
+		close(done)                    // оба воркера обрабатывают значение
+
+		drainChan(p.Out())
+		require.ErrorIs(t, p.Wait(), context.Canceled)
+		require.Equal(t, int32(2), atomic.LoadInt32(&calls), "pending values were processed after done")
+	})
+}
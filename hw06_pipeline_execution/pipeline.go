@@ -1,5 +1,11 @@
 package hw06pipelineexecution
 
+import (
+	"context"
+	"sync"
+	"time"
+)
+
 type (
 	In  = <-chan interface{}
 	Out = In
@@ -8,24 +14,223 @@ type (
 
 type Stage func(in In) (out Out)
 
+// TypedStage - стейдж, преобразующий значения типа I в значения типа O.
+// Стейдж должен закрыть выходной канал после закрытия входного.
+// Контекст пайплайна передается для прерывания длительной обработки значения.
+type TypedStage[I, O any] func(ctx context.Context, in <-chan I) <-chan O
+
 func ExecutePipeline(in In, done In, stages ...Stage) Out {
-	output := in
+	return RunPipeline(in, done, stages...).Out()
+}
+
+// RunPipeline запускает стейджи, как ExecutePipeline, и возвращает запущенный пайплайн.
+// После закрытия done промежуточные каналы вычитываются до закрытия стейджами,
+// а Wait позволяет дождаться завершения всех горутин пайплайна.
+func RunPipeline(in In, done In, stages ...Stage) *Pipeline[interface{}] {
+	return RunPipelineWith(in, done, nil, stages...)
+}
+
+// RunPipelineWith запускает стейджи, как RunPipeline, с параметрами opts,
+// например размерами буферов между стейджами и политикой ошибок.
+// Стейдж сообщает об ошибке, отправляя ItemError в выходной канал (см. TryStage); ошибка с номером стейджа
+// обрабатывается согласно WithErrorPolicy и возвращается Err и Wait. По умолчанию первая ошибка
+// останавливает пайплайн, как закрытие done.
+func RunPipelineWith(in In, done In, opts []Option, stages ...Stage) *Pipeline[interface{}] {
+	typed := make([]TypedStage[interface{}, interface{}], len(stages))
+	for i, stage := range stages {
+		typed[i] = FromStage(stage)
+	}
+
+	// контекст отменяется только закрытием done, чтобы Err не возвращал ошибку после обычного завершения
+	ctx, cancel := context.WithCancel(context.Background())
+	finished := make(chan struct{})
+	p := startPipeline(ctx, in, Stages(typed...), func() { close(finished) }, opts)
+
+	p.state.wg.Add(1)
+	go func() {
+		defer p.state.wg.Done()
+
+		select {
+		case <-done: // nil done никогда не закрывается
+			cancel()
+		case <-finished:
+		}
+	}()
+
+	return p
+}
+
+// FromStage преобразует Stage в типизированный стейдж, например для запуска через StartPipeline.
+// Значения ItemError из выхода stage не передаются дальше, а обрабатываются как ошибки стейджа.
+func FromStage(stage Stage) TypedStage[interface{}, interface{}] {
+	return func(ctx context.Context, in <-chan interface{}) <-chan interface{} {
+		idx := stageIndex(ctx)
+		res := stage(in)
+		out := make(chan interface{})
+
+		goStage(ctx, func() {
+			defer drainChan(res)
+			defer close(out)
+
+			for v := range res {
+				if ie, ok := v.(ItemError); ok {
+					reportError(ctx, StageError{Stage: idx, Item: ie.Item, Err: ie.Err})
+					continue
+				}
+				if !send(ctx, out, v) {
+					return
+				}
+			}
+		})
+
+		return out
+	}
+}
+
+// ExecuteTypedPipeline запускает стейдж stage, как правило составленный через Chain, над значениями из in.
+// После отмены ctx выходной канал закрывается, а значения, которые стейджи продолжают отдавать
+// в промежуточные каналы, вычитываются и отбрасываются, чтобы стейджи не блокировались.
+func ExecuteTypedPipeline[I, O any](ctx context.Context, in <-chan I, stage TypedStage[I, O]) <-chan O {
+	return StartPipeline(ctx, in, stage).Out()
+}
+
+// Pipeline - запущенный пайплайн.
+type Pipeline[O any] struct {
+	out    <-chan O
+	parent context.Context
+	state  *pipelineState
+}
+
+// Out - выходной канал пайплайна. Канал необходимо читать до закрытия.
+func (p *Pipeline[O]) Out() <-chan O {
+	return p.out
+}
+
+// Err возвращает ошибку пайплайна после закрытия выходного канала:
+// первую ошибку стейджа при FailFast, StageErrors при CollectErrors, ошибку ctx при его отмене.
+func (p *Pipeline[O]) Err() error {
+	if err := p.state.err(); err != nil {
+		return err
+	}
+
+	return p.parent.Err()
+}
+
+// Wait ожидает завершения пайплайна и возвращает его ошибку, как Err.
+// Пайплайн завершается, когда выходной канал прочитан до закрытия или пайплайн остановлен.
+// После возврата Wait горутины пайплайна и стейджи завершены: каждый стейдж закрыл выходной канал,
+// а горутины стейджей, созданных функциями пакета, завершились.
+func (p *Pipeline[O]) Wait() error {
+	p.state.wg.Wait()
+	return p.Err()
+}
 
-	for _, stage := range stages {
-		input := chanWrap(output, done)
-		output = stage(input)
+// StartPipeline запускает стейдж stage над значениями из in, как ExecuteTypedPipeline,
+// и позволяет получить ошибку стейджей после завершения.
+// Ошибки стейджей, созданных через TryMap, обрабатываются согласно WithErrorPolicy:
+// при FailFast пайплайн останавливается так же, как при отмене ctx.
+func StartPipeline[I, O any](ctx context.Context, in <-chan I, stage TypedStage[I, O], opts ...Option) *Pipeline[O] {
+	return startPipeline(ctx, in, stage, nil, opts)
+}
+
+// запустить пайплайн; stop, если задан, вызывается после закрытия выходного канала.
+func startPipeline[I, O any](
+	parent context.Context, in <-chan I, stage TypedStage[I, O], stop func(), opts []Option,
+) *Pipeline[O] {
+	ctx, cancel := context.WithCancel(parent)
+	state := &pipelineState{cfg: newConfig(opts), cancel: cancel}
+	ctx = context.WithValue(ctx, stateKey{}, state)
+
+	// входной канал принадлежит вызывающему, поэтому после отмены он не вычитывается
+	out := stage(ctx, wrap(in, ctx.Done(), state.boundary(false)))
+
+	outOpts := state.boundary(true)
+	outOpts.stop = func() {
+		cancel()
+		if stop != nil {
+			stop()
+		}
+	}
+
+	return &Pipeline[O]{
+		out:    wrap(out, ctx.Done(), outOpts),
+		parent: parent,
+		state:  state,
+	}
+}
+
+// Chain соединяет стейджи first и second: выход first становится входом second.
+func Chain[A, B, C any](first TypedStage[A, B], second TypedStage[B, C]) TypedStage[A, C] {
+	return func(ctx context.Context, in <-chan A) <-chan C {
+		mid := first(ctx, in)
+		nextStage(ctx)
+
+		opts := wrapOptions{drain: true}
+		if state := stateFrom(ctx); state != nil {
+			opts = state.boundary(true)
+		}
+
+		return second(ctx, wrap(mid, ctx.Done(), opts))
+	}
+}
+
+// Stages соединяет последовательно стейджи с одинаковым типом входа и выхода.
+// Без стейджей значения передаются без изменений.
+func Stages[T any](stages ...TypedStage[T, T]) TypedStage[T, T] {
+	if len(stages) == 0 {
+		return func(_ context.Context, in <-chan T) <-chan T {
+			return in
+		}
+	}
+
+	stage := stages[0]
+	for _, next := range stages[1:] {
+		stage = Chain(stage, next)
 	}
 
-	return output
+	return stage
 }
 
 func chanWrap(in In, done In) Out {
-	out := make(Bi)
+	return wrap(in, done, wrapOptions{})
+}
+
+// параметры передачи значений между стейджами.
+type wrapOptions struct {
+	drain  bool            // вычитывать вход после закрытия done
+	buffer int             // размер буфера выходного канала
+	meter  *boundary       // учет метрик, nil - без учета
+	stop   func()          // вызывается после закрытия выходного канала
+	wg     *sync.WaitGroup // учет завершения горутины, nil - без учета
+}
 
+// передать значения из in в выходной канал до закрытия in или done.
+// При opts.drain после закрытия done значения из in вычитываются до его закрытия,
+// чтобы пишущий в in стейдж мог завершиться.
+func wrap[T, D any](in <-chan T, done <-chan D, opts wrapOptions) <-chan T {
+	out := make(chan T, max(opts.buffer, 0))
+	opts.meter.watch(func() int { return len(out) }, cap(out))
+
+	if opts.wg != nil {
+		opts.wg.Add(1)
+	}
 	go func() {
-		defer close(out)
+		defer func() {
+			if opts.wg != nil {
+				defer opts.wg.Done()
+			}
+
+			close(out)
+			if opts.stop != nil {
+				opts.stop()
+			}
+			if opts.drain {
+				drainChan(in)
+			}
+		}()
 
 		for {
+			start := time.Now()
 			select {
 			case <-done:
 				return
@@ -33,10 +238,17 @@ func chanWrap(in In, done In) Out {
 				if !ok {
 					return
 				}
+				opts.meter.received(time.Since(start))
+				if isClosed(done) { // select выбирает случайно, если готовы оба канала
+					return
+				}
+
+				start = time.Now()
 				select {
 				case <-done:
 					return
 				case out <- val:
+					opts.meter.sent(time.Since(start))
 				}
 			}
 		}
@@ -44,3 +256,22 @@ func chanWrap(in In, done In) Out {
 
 	return out
 }
+
+// канал закрыт.
+func isClosed[T any](ch <-chan T) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// вычитать значения из канала до его закрытия.
+func drainChan[T any](in <-chan T) {
+	for {
+		if _, ok := <-in; !ok {
+			return
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package hw06pipelineexecution
 
 import (
+	"context"
 	"strconv"
 	"sync"
 	"testing"
@@ -273,3 +274,102 @@ func TestChanWrap(t *testing.T) {
 		require.Equal(t, []int{1, 2}, res)
 	})
 }
+
+func TestTypedPipeline(t *testing.T) {
+	// стейдж, применяющий f к каждому значению
+	m := func(f func(v int) int) TypedStage[int, int] {
+		return func(_ context.Context, in <-chan int) <-chan int {
+			out := make(chan int)
+			go func() {
+				defer close(out)
+				for v := range in {
+					out <- f(v)
+				}
+			}()
+			return out
+		}
+	}
+	stringify := func(_ context.Context, in <-chan int) <-chan string {
+		out := make(chan string)
+		go func() {
+			defer close(out)
+			for v := range in {
+				out <- strconv.Itoa(v)
+			}
+		}()
+		return out
+	}
+	source := func(data ...int) <-chan int {
+		in := make(chan int)
+		go func() {
+			defer close(in)
+			for _, v := range data {
+				in <- v
+			}
+		}()
+		return in
+	}
+
+	t.Run("chain", func(t *testing.T) {
+		stage := Chain(Stages(m(func(v int) int { return v * 2 }), m(func(v int) int { return v + 100 })), stringify)
+
+		result := make([]string, 0, 5)
+		for s := range ExecuteTypedPipeline(context.Background(), source(1, 2, 3, 4, 5), stage) {
+			result = append(result, s)
+		}
+
+		require.Equal(t, []string{"102", "104", "106", "108", "110"}, result)
+	})
+
+	t.Run("no stages", func(t *testing.T) {
+		result := make([]int, 0, 3)
+		for v := range ExecuteTypedPipeline(context.Background(), source(1, 2, 3), Stages[int]()) {
+			result = append(result, v)
+		}
+
+		require.Equal(t, []int{1, 2, 3}, result)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		wg := sync.WaitGroup{}
+		slow := func(_ context.Context, in <-chan int) <-chan int {
+			out := make(chan int)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer close(out)
+				for v := range in {
+					time.Sleep(10 * time.Millisecond)
+					out <- v
+				}
+			}()
+			return out
+		}
+
+		in := make(chan int)
+		go func() {
+			for i := 0; ; i++ {
+				select {
+				case <-ctx.Done():
+					return
+				case in <- i:
+				}
+			}
+		}()
+
+		result := make([]int, 0)
+		for v := range ExecuteTypedPipeline(ctx, in, Stages(slow, slow, slow)) {
+			result = append(result, v)
+			if len(result) == 3 {
+				cancel()
+			}
+		}
+		wg.Wait() // стейджи, заблокированные на записи, завершаются
+
+		require.GreaterOrEqual(t, len(result), 3)
+		require.LessOrEqual(t, len(result), 4)
+	})
+}
@@ -0,0 +1,269 @@
+package hw06pipelineexecution
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// состояние запущенного пайплайна, доступное стейджам через контекст.
+type pipelineState struct {
+	mtx        sync.Mutex
+	cfg        config             // параметры пайплайна
+	stage      int                // номер запускаемого стейджа
+	errs       StageErrors        // ошибки стейджей
+	boundaries []*boundary        // границы между стейджами, i-я - вход i-го стейджа
+	cancel     context.CancelFunc // остановка пайплайна
+	wg         sync.WaitGroup     // горутины пайплайна
+	replay     []*boundary        // границы для повторного использования при построении копий стейджа
+}
+
+type stateKey struct{}
+
+func stateFrom(ctx context.Context) *pipelineState {
+	state, _ := ctx.Value(stateKey{}).(*pipelineState)
+	return state
+}
+
+// перейти к следующему стейджу. Стейджи запускаются по порядку, поэтому номер,
+// прочитанный стейджем при запуске, совпадает с его позицией в пайплайне.
+func nextStage(ctx context.Context) {
+	if state := stateFrom(ctx); state != nil {
+		state.mtx.Lock()
+		state.stage++
+		state.mtx.Unlock()
+	}
+}
+
+// создать следующую границу между стейджами и параметры передачи значений через нее.
+func (s *pipelineState) boundary(drain bool) wrapOptions {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	buffer := s.cfg.buffer
+	if size, ok := s.cfg.stageBuffers[len(s.boundaries)]; ok {
+		buffer = size
+	}
+
+	var b *boundary
+	if len(s.replay) > 0 { // копия стейджа использует границы первой копии
+		b, s.replay = s.replay[0], s.replay[1:]
+	} else {
+		b = &boundary{}
+		s.boundaries = append(s.boundaries, b)
+	}
+
+	return wrapOptions{drain: drain, buffer: buffer, meter: b, wg: &s.wg}
+}
+
+// запустить горутину стейджа; Pipeline.Wait ожидает ее завершения.
+// Горутины запускаются при построении пайплайна, до возможного вызова Wait.
+func goStage(ctx context.Context, f func()) {
+	state := stateFrom(ctx)
+	if state == nil {
+		go f()
+		return
+	}
+
+	state.wg.Add(1)
+	go func() {
+		defer state.wg.Done()
+		f()
+	}()
+}
+
+// отметка о состоянии построения пайплайна.
+type buildMark struct {
+	stage      int         // номер запускаемого стейджа
+	replay     []*boundary // оставшиеся границы для повторного использования
+	boundaries int         // количество созданных границ
+}
+
+// отметить состояние построения перед построением первой копии стейджа.
+func (s *pipelineState) mark() buildMark {
+	if s == nil {
+		return buildMark{}
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return buildMark{stage: s.stage, replay: s.replay, boundaries: len(s.boundaries)}
+}
+
+// построить копии стейджа с номерами от 1 до n-1 после первой копии, построенной с отметки m.
+// Копии получают те же номера стейджей, что и первая, и используют ее границы,
+// поэтому метрики границ суммируются по всем копиям.
+func (s *pipelineState) replicate(m buildMark, n int, build func(i int)) {
+	if s == nil {
+		for i := 1; i < n; i++ {
+			build(i)
+		}
+		return
+	}
+
+	s.mtx.Lock()
+	end := buildMark{stage: s.stage, replay: s.replay}
+	// границы первой копии: повторно использованные и созданные ею
+	used := slices.Clone(m.replay[:len(m.replay)-len(s.replay)])
+	used = append(used, s.boundaries[m.boundaries:]...)
+	s.mtx.Unlock()
+
+	for i := 1; i < n; i++ {
+		s.mtx.Lock()
+		s.stage = m.stage
+		s.replay = append(slices.Clone(used), end.replay...)
+		s.mtx.Unlock()
+
+		build(i)
+	}
+
+	s.mtx.Lock()
+	s.stage, s.replay = end.stage, end.replay
+	s.mtx.Unlock()
+}
+
+// запустить типизированный стейдж как Stage. Контекст стейджа отменяется при закрытии done,
+// ошибки стейджа обрабатываются согласно policy, но не возвращаются: у Stage нет способа их передать.
+// Выходной канал закрывается после завершения всех горутин стейджа.
+func untypedStage[I, O any](done In, policy ErrorPolicy, stage TypedStage[I, O]) Stage {
+	return func(in In) Out {
+		ctx, cancel := context.WithCancel(context.Background())
+		state := &pipelineState{cfg: config{errorPolicy: policy}, cancel: cancel}
+		ctx = context.WithValue(ctx, stateKey{}, state)
+
+		goStage(ctx, func() {
+			select {
+			case <-done: // nil done никогда не закрывается
+				cancel()
+			case <-ctx.Done():
+			}
+		})
+
+		typed := make(chan I)
+		goStage(ctx, func() {
+			defer close(typed)
+
+			for v := range in {
+				if !send(ctx, typed, v.(I)) {
+					return
+				}
+			}
+		})
+
+		res := stage(ctx, typed)
+		out := make(Bi)
+
+		// Stage не получает контекст пайплайна: горутина закрывает out последней,
+		// после завершения остальных горутин стейджа, поэтому пайплайн дожидается их по закрытию выхода
+		go func() {
+			defer close(out)
+
+			for v := range res {
+				send(ctx, out, interface{}(v)) // после отмены значения отбрасываются
+			}
+			cancel()
+			state.wg.Wait()
+		}()
+
+		return out
+	}
+}
+
+// номер запускаемого стейджа.
+func stageIndex(ctx context.Context) int {
+	state := stateFrom(ctx)
+	if state == nil {
+		return 0
+	}
+
+	state.mtx.Lock()
+	defer state.mtx.Unlock()
+
+	return state.stage
+}
+
+// обработать ошибку стейджа согласно политике пайплайна.
+func reportError(ctx context.Context, se StageError) {
+	state := stateFrom(ctx)
+	if state == nil {
+		return
+	}
+
+	state.mtx.Lock()
+	defer state.mtx.Unlock()
+
+	switch state.cfg.errorPolicy {
+	case FailFast:
+		if len(state.errs) == 0 {
+			state.errs = append(state.errs, se)
+			state.cancel()
+		}
+	case CollectErrors:
+		state.errs = append(state.errs, se)
+	case SkipErrors:
+	}
+}
+
+// ошибка пайплайна согласно политике.
+func (s *pipelineState) err() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	switch {
+	case len(s.errs) == 0:
+		return nil
+	case s.cfg.errorPolicy == FailFast:
+		return s.errs[0]
+	default:
+		return append(StageErrors(nil), s.errs...)
+	}
+}
+
+// TryMap создает стейдж, применяющий f к каждому значению.
+// Если f вернула ошибку, значение отбрасывается, а ошибка с номером стейджа и значением
+// обрабатывается согласно политике пайплайна, заданной WithErrorPolicy.
+func TryMap[I, O any](f func(ctx context.Context, v I) (O, error)) TypedStage[I, O] {
+	return func(ctx context.Context, in <-chan I) <-chan O {
+		idx := stageIndex(ctx)
+		out := make(chan O)
+
+		goStage(ctx, func() {
+			defer close(out)
+
+			for v := range in {
+				res, err := f(ctx, v)
+				if err != nil {
+					reportError(ctx, StageError{Stage: idx, Item: v, Err: err})
+					continue
+				}
+				out <- res
+			}
+		})
+
+		return out
+	}
+}
+
+// TryStage создает Stage, применяющий f к каждому значению.
+// Если f вернула ошибку, вместо результата в выходной канал отправляется ItemError,
+// которую ExecutePipeline и RunPipelineWith обрабатывают согласно политике ошибок.
+func TryStage(f func(v interface{}) (interface{}, error)) Stage {
+	return func(in In) Out {
+		out := make(Bi)
+
+		go func() {
+			defer close(out)
+
+			for v := range in {
+				res, err := f(v)
+				if err != nil {
+					res = ItemError{Item: v, Err: err}
+				}
+				out <- res
+			}
+		}()
+
+		return out
+	}
+}
@@ -0,0 +1,126 @@
+package hw06pipelineexecution
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestPipelineWait(t *testing.T) {
+	var running int32
+	// стейдж, блокирующийся на записи, пока следующий не прочитает значение
+	g := func(in In) Out {
+		out := make(Bi)
+		atomic.AddInt32(&running, 1)
+		go func() {
+			defer close(out)
+			defer atomic.AddInt32(&running, -1)
+			for v := range in {
+				time.Sleep(time.Millisecond)
+				out <- v
+			}
+		}()
+		return out
+	}
+
+	t.Run("early done", func(t *testing.T) {
+		before := goleak.IgnoreCurrent()
+
+		in := make(Bi)
+		done := make(Bi)
+		sourceDone := make(chan struct{})
+		go func() {
+			defer close(sourceDone)
+			defer close(in)
+			for i := 0; ; i++ {
+				select {
+				case <-done:
+					return
+				case in <- i:
+				}
+			}
+		}()
+
+		p := RunPipeline(in, done, g, g, g, g)
+		<-p.Out()
+		close(done) // выход больше не читается, стейджи блокированы на записи
+
+		require.ErrorIs(t, p.Wait(), context.Canceled)
+		<-sourceDone
+		goleak.VerifyNone(t, before)
+		require.Zero(t, atomic.LoadInt32(&running))
+		_, ok := <-p.Out()
+		require.False(t, ok)
+	})
+
+	t.Run("completed", func(t *testing.T) {
+		before := goleak.IgnoreCurrent()
+
+		in := make(Bi, 3)
+		for i := 1; i <= 3; i++ {
+			in <- i
+		}
+		close(in)
+
+		p := RunPipeline(in, make(Bi), g, g)
+		require.Len(t, collectAll(p.Out()), 3)
+
+		require.NoError(t, p.Wait())
+		goleak.VerifyNone(t, before)
+		require.Zero(t, atomic.LoadInt32(&running))
+	})
+
+	t.Run("typed stages", func(t *testing.T) {
+		before := goleak.IgnoreCurrent()
+
+		in := make(chan int, 100)
+		for i := 0; i < 100; i++ {
+			in <- i
+		}
+		close(in)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		slow := Parallel(4, Ordered, func(_ context.Context, v int) (int, error) {
+			time.Sleep(time.Millisecond)
+			return v, nil
+		})
+		inc := FanOut(2, Map(func(v int) int { return v + 1 }))
+		p := StartPipeline(ctx, in, Chain(Stages(inc, slow), Batch[int](3, time.Hour)))
+
+		<-p.Out()
+		cancel()
+
+		require.ErrorIs(t, p.Wait(), context.Canceled)
+		goleak.VerifyNone(t, before)
+	})
+
+	t.Run("fail fast", func(t *testing.T) {
+		before := goleak.IgnoreCurrent()
+
+		// входной канал не вычитывается после остановки, поэтому источник не должен блокироваться
+		in := make(chan int, 5)
+		for _, v := range []int{2, 4, 5, 6, 8} {
+			in <- v
+		}
+		close(in)
+
+		p := StartPipeline(context.Background(), in, TryMap(
+			func(_ context.Context, v int) (int, error) {
+				if v%2 != 0 {
+					return 0, errOdd
+				}
+				return v, nil
+			}))
+		collectAll(p.Out())
+
+		var se StageError
+		require.True(t, errors.As(p.Wait(), &se))
+		require.Equal(t, 5, se.Item)
+		goleak.VerifyNone(t, before)
+	})
+}